@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/detector"
+
+	"sigs.k8s.io/yaml"
+)
+
+// clusterTarget is one cluster to watch
+type clusterTarget struct {
+	// Name tags failures from this cluster; empty when only one is watched
+	Name       string `json:"name"`
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+	Context    string `json:"context,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+}
+
+// clustersFile is the --clusters-file format:
+//
+//	clusters:
+//	- name: staging
+//	  context: staging-admin
+//	- name: prod-eu
+//	  kubeconfig: /etc/detective/prod-eu.yaml
+//	  namespace: payments
+type clustersFile struct {
+	Clusters []clusterTarget `json:"clusters"`
+}
+
+// resolveClusters works out which clusters to watch from --clusters-file and
+// repeated --context flags, falling back to a single untagged cluster
+func resolveClusters(kube kubeFlags, path, namespace string) ([]clusterTarget, error) {
+	var targets []clusterTarget
+
+	switch {
+	case path != "":
+		if len(kube.contexts) > 0 {
+			return nil, fmt.Errorf("cannot use both --clusters-file and --context")
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read clusters file: %w", err)
+		}
+		var file clustersFile
+		if err := yaml.UnmarshalStrict(data, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse clusters file %s: %w", path, err)
+		}
+		if len(file.Clusters) == 0 {
+			return nil, fmt.Errorf("clusters file %s lists no clusters", path)
+		}
+		targets = file.Clusters
+
+	case len(kube.contexts) > 1:
+		for _, name := range kube.contexts {
+			targets = append(targets, clusterTarget{Name: name, Context: name})
+		}
+
+	default:
+		target := clusterTarget{Namespace: namespace}
+		if len(kube.contexts) == 1 {
+			target.Context = kube.contexts[0]
+		}
+		return []clusterTarget{target}, nil
+	}
+
+	seen := make(map[string]bool)
+	for i := range targets {
+		if targets[i].Name == "" {
+			targets[i].Name = targets[i].Context
+		}
+		if targets[i].Name == "" {
+			return nil, fmt.Errorf("cluster %d needs a name or a context", i+1)
+		}
+		if seen[targets[i].Name] {
+			return nil, fmt.Errorf("cluster %q is listed twice", targets[i].Name)
+		}
+		seen[targets[i].Name] = true
+		if targets[i].Namespace == "" {
+			targets[i].Namespace = namespace
+		}
+	}
+	return targets, nil
+}
+
+// flags returns the connection flags for this cluster
+func (t clusterTarget) flags(kube kubeFlags) kubeFlags {
+	kube.context = t.Context
+	kube.contexts = nil
+	if t.Kubeconfig != "" {
+		kube.kubeconfig = t.Kubeconfig
+	}
+	return kube
+}
+
+// syncWriter lets several detectors share stdout without interleaving
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
+
+// detectorGroup fans leadership out to every detector and reports the most
+// recent poll across clusters, so one unreachable cluster doesn't fail /healthz
+type detectorGroup []*detector.PodDetector
+
+func (g detectorGroup) SetLeading(leading bool, handover time.Time) {
+	for _, d := range g {
+		d.SetLeading(leading, handover)
+	}
+}
+
+func (g detectorGroup) LastSync() time.Time {
+	var latest time.Time
+	for _, d := range g {
+		if last := d.LastSync(); last.After(latest) {
+			latest = last
+		}
+	}
+	return latest
+}
+
+// watchCluster keeps one cluster's detector running, retrying with backoff
+// while the cluster is unreachable instead of stopping the other clusters
+func watchCluster(ctx context.Context, d *detector.PodDetector, target clusterTarget) {
+	backoff := 5 * time.Second
+	const maxBackoff = 5 * time.Minute
+
+	for {
+		err := d.WatchPods(ctx, target.Namespace)
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Error("Cluster unreachable, retrying", "cluster", target.Name, "retryIn", backoff, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
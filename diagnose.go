@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/inspect"
+)
+
+// runDiagnose runs every check against one pod, healthy or not, and prints a
+// single report
+func runDiagnose(args []string) {
+	fs := newFlagSet("diagnose", "diagnose POD [flags]")
+	var kube kubeFlags
+	kube.register(fs)
+	var logs logFlags
+	logs.register(fs)
+	logLines := fs.Int64("tail", 20, "number of log lines to show from each container instance")
+
+	positional := parseInterspersed(fs, args)
+	if len(positional) != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	logs.setup()
+	clientset := newClientset(kube)
+
+	report, err := inspect.Pod(context.Background(), clientset, resolveNamespace(kube), positional[0],
+		inspect.Options{LogLines: *logLines})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	report.Write(os.Stdout)
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/detector"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/explainer"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/offline"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// explainUsage lists the forms of the explain command
+const explainUsage = "explain -f FILE|- [-f FILE...] [flags] | reason REASON | exit-code CODE | list"
+
+// runExplain explains failures in saved 'kubectl get -o yaml' output, or looks
+// up a reason or exit code, without talking to a cluster
+func runExplain(args []string) {
+	if len(args) > 0 {
+		switch args[0] {
+		case "reason":
+			runExplainReason(args[1:])
+			return
+		case "exit-code":
+			runExplainExitCode(args[1:])
+			return
+		case "list":
+			runExplainList(args[1:])
+			return
+		}
+	}
+
+	fs := newFlagSet("explain", explainUsage)
+	var files, eventFiles, logFiles stringSlice
+	fs.Var(&files, "f", "pod manifest or List (YAML or JSON) to explain, or '-' for stdin (repeatable)")
+	fs.Var(&eventFiles, "events", "(optional) 'kubectl get events -o yaml' output to add to the diagnosis (repeatable)")
+	fs.Var(&logFiles, "logs", "(optional) log file as FILE for every container, or POD=FILE or POD/CONTAINER=FILE (repeatable)")
+	var logs logFlags
+	logs.register(fs)
+	fs.Parse(args)
+
+	if len(files) == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	logs.setup()
+
+	var objects []runtime.Object
+	for _, file := range append(files, eventFiles...) {
+		decoded, err := decodeFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", file, err)
+			os.Exit(1)
+		}
+		objects = append(objects, decoded...)
+	}
+
+	logSource := &offline.Logs{}
+	for _, value := range logFiles {
+		target, path, ok := strings.Cut(value, "=")
+		if !ok {
+			target, path = "", value
+		}
+		if err := logSource.Add(target, path); err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading log file: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	pods, failing := explainDump(os.Stdout, objects, logSource)
+	switch {
+	case pods == 0:
+		fmt.Fprintf(os.Stderr, "Error: no pods found in %s\n", strings.Join(files, ", "))
+		os.Exit(1)
+	case failing == 0:
+		fmt.Printf("✅ No failing containers in %d pods\n", pods)
+	}
+}
+
+// explainDump writes the explanation of every failing pod in the decoded
+// objects, and returns how many pods there were and how many failed
+func explainDump(w io.Writer, objects []runtime.Object, logs detector.LogSource) (int, int) {
+	// The detector reads everything but the pods through its client:
+	// ReplicaSets and Jobs let failures name their Deployment or CronJob,
+	// Events explain probe failures and Nodes evictions
+	var (
+		pods   []*corev1.Pod
+		events []corev1.Event
+		others []runtime.Object
+	)
+	for _, object := range objects {
+		switch object := object.(type) {
+		case *corev1.Pod:
+			pods = append(pods, object)
+		case *corev1.Event:
+			events = append(events, *object)
+			others = append(others, object)
+		default:
+			others = append(others, object)
+		}
+	}
+
+	podDetector := detector.New(fake.NewClientset(others...), detector.Options{Logs: logs})
+	ctx := context.Background()
+
+	failing := 0
+	for _, pod := range pods {
+		failures := podDetector.Failures(ctx, pod)
+		if len(failures) == 0 {
+			continue
+		}
+		failing++
+
+		for _, info := range failures {
+			fmt.Fprintln(w, explainer.Explain(info))
+			printEvents(w, offline.EventsFor(events, pod))
+			fmt.Fprintln(w, "=====================================")
+			fmt.Fprintln(w)
+		}
+	}
+	return len(pods), failing
+}
+
+// runExplainReason prints the explanation of a reason with placeholder names
+func runExplainReason(args []string) {
+	fs := newFlagSet("explain reason", "explain reason REASON [--exit-code CODE]")
+	exitCode := fs.Int("exit-code", 0, "(optional) exit code to explain along with the reason (e.g., 137)")
+	positional := parseInterspersed(fs, args)
+	if len(positional) != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	reason, ok := explainer.LookupReason(positional[0])
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: unknown reason %q; run '%s explain list' for the known ones\n", positional[0], programName())
+		os.Exit(1)
+	}
+
+	info := explainer.Placeholder(reason.Name, int32(*exitCode))
+	fmt.Println(explainer.Explain(info))
+	fmt.Printf("Severity: %s\n", explainer.SeverityOf(info))
+}
+
+// runExplainExitCode prints what a container exit code means
+func runExplainExitCode(args []string) {
+	fs := newFlagSet("explain exit-code", "explain exit-code CODE")
+	positional := parseInterspersed(fs, args)
+	if len(positional) != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	code, err := strconv.ParseInt(positional[0], 10, 32)
+	if err != nil || code < 0 || code > 255 {
+		fmt.Fprintf(os.Stderr, "Error: invalid exit code %q, expected a number from 0 to 255\n", positional[0])
+		os.Exit(1)
+	}
+
+	info := explainer.Placeholder("Error", int32(code))
+	fmt.Println(explainer.ExplainExitCode(info.ExitCode))
+	fmt.Printf("Severity when a container exits with it: %s\n", explainer.SeverityOf(info))
+	if code == 137 {
+		fmt.Printf("\nIf the container status says OOMKilled, see '%s explain reason OOMKilled'.\n", programName())
+	}
+}
+
+// runExplainList lists every reason and exit code the explainer knows
+func runExplainList(args []string) {
+	fs := newFlagSet("explain list", "explain list")
+	fs.Parse(args)
+
+	fmt.Println("📚 KNOWN REASONS:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, reason := range explainer.Reasons() {
+		severity := explainer.SeverityOf(explainer.Placeholder(reason.Name, 0))
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", reason.Name, severity, reason.Description)
+	}
+	w.Flush()
+
+	fmt.Println("\n🔢 KNOWN EXIT CODES:")
+	for _, code := range explainer.ExitCodes() {
+		fmt.Printf("  %s\n", strings.TrimPrefix(explainer.ExplainExitCode(code), "→ "))
+	}
+	fmt.Println("  Codes 129-159 mean the container was killed by signal CODE-128.")
+
+	fmt.Printf("\nRun '%s explain reason REASON' or '%s explain exit-code CODE' for details.\n", programName(), programName())
+}
+
+// decodeFile decodes a file, or stdin for "-"
+func decodeFile(path string) ([]runtime.Object, error) {
+	if path == "-" {
+		return offline.Decode(os.Stdin)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return offline.Decode(f)
+}
+
+func printEvents(w io.Writer, events []corev1.Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "📅 EVENTS:\n")
+	for _, event := range events {
+		count := ""
+		if event.Count > 1 {
+			count = fmt.Sprintf(" (x%d)", event.Count)
+		}
+		fmt.Fprintf(w, "%s %s%s: %s\n", event.Type, event.Reason, count, strings.TrimSpace(event.Message))
+	}
+	fmt.Fprintln(w)
+}
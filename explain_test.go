@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/offline"
+)
+
+// livenessDump is a pod killed by its liveness probe and its events, as
+// 'kubectl get pod,events -o yaml' saves them
+const livenessDump = `
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Pod
+  metadata:
+    name: api
+    namespace: shop
+    uid: 1b2c
+  spec:
+    containers:
+    - name: app
+      image: shop/api:1.4
+      livenessProbe:
+        httpGet:
+          path: /healthz
+          port: 8080
+        periodSeconds: 5
+        failureThreshold: 3
+  status:
+    phase: Running
+    containerStatuses:
+    - name: app
+      restartCount: 6
+      state:
+        waiting:
+          reason: CrashLoopBackOff
+          message: back-off 2m40s restarting failed container
+      lastState:
+        terminated:
+          exitCode: 137
+          reason: Error
+          startedAt: "2026-10-18T09:00:00Z"
+          finishedAt: "2026-10-18T09:00:20Z"
+- apiVersion: v1
+  kind: Event
+  metadata:
+    name: api.unhealthy
+    namespace: shop
+  involvedObject:
+    kind: Pod
+    name: api
+    namespace: shop
+    uid: 1b2c
+    fieldPath: spec.containers{app}
+  reason: Unhealthy
+  type: Warning
+  count: 18
+  message: 'Liveness probe failed: Get "http://10.1.2.3:8080/healthz": dial tcp 10.1.2.3:8080: connect: connection refused'
+  lastTimestamp: "2026-10-18T09:00:15Z"
+- apiVersion: v1
+  kind: Event
+  metadata:
+    name: api.killing
+    namespace: shop
+  involvedObject:
+    kind: Pod
+    name: api
+    namespace: shop
+    uid: 1b2c
+    fieldPath: spec.containers{app}
+  reason: Killing
+  type: Normal
+  count: 6
+  message: Container app failed liveness probe, will be restarted
+  lastTimestamp: "2026-10-18T09:00:20Z"
+`
+
+func TestExplainDumpBlamesLivenessProbe(t *testing.T) {
+	objects, err := offline.Decode(strings.NewReader(livenessDump))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	pods, failing := explainDump(&out, objects, &offline.Logs{})
+	if pods != 1 || failing != 1 {
+		t.Fatalf("explained %d failing of %d pods, want 1 of 1", failing, pods)
+	}
+
+	explanation := out.String()
+	for _, want := range []string{
+		"its liveness probe fails",
+		"livenessProbe: HTTP GET :8080/healthz",
+		"Failed checks: 18",
+		"connect: connection refused",
+		"LIKELY MISCONFIGURATION",
+	} {
+		if !strings.Contains(explanation, want) {
+			t.Errorf("explanation lacks %q:\n%s", want, explanation)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/detector"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/gather"
+)
+
+// runGather collects a must-gather archive for some pods, or for every
+// failing pod of a namespace
+func runGather(args []string) {
+	fs := newFlagSet("gather", "gather [POD...] [flags]")
+	var kube kubeFlags
+	kube.register(fs)
+	var logs logFlags
+	logs.register(fs)
+	output := fs.String("o", "", "(optional) archive to write (default: pod-detective-gather-NAMESPACE[-POD]-TIMESTAMP.tar.gz)")
+	logLines := fs.Int64("tail", 1000, "number of log lines to keep from each container instance")
+	skipCommands := fs.Bool("skip-commands", false, "don't run the suggested kubectl debug commands")
+	commandTimeout := fs.Duration("command-timeout", 30*time.Second, "how long each kubectl debug command may run")
+
+	podNames := parseInterspersed(fs, args)
+	logs.setup()
+
+	namespace := resolveNamespace(kube)
+	clientset := newClientset(kube)
+	ctx := context.Background()
+
+	target := namespace
+	if len(podNames) == 1 {
+		target += "-" + podNames[0]
+	}
+
+	// Without pod names, gather every failing pod of the namespace
+	if len(podNames) == 0 {
+		failures, err := detector.New(clientset, detector.Options{}).Scan(ctx, namespace)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error scanning pods: %v\n", err)
+			os.Exit(1)
+		}
+		for _, info := range failures {
+			if !slices.Contains(podNames, info.PodName) {
+				podNames = append(podNames, info.PodName)
+			}
+		}
+	}
+
+	root := fmt.Sprintf("pod-detective-gather-%s-%s", target, time.Now().UTC().Format("20060102-150405"))
+	path := *output
+	if path == "" {
+		path = root + ".tar.gz"
+	}
+
+	opts := gather.Options{LogLines: *logLines}
+	if !*skipCommands {
+		commands, err := gather.NewCommands(kube.kubectlArgs())
+		if err != nil {
+			slog.Warn("Not running debug commands, kubectl not found", "error", err)
+		} else {
+			commands.Timeout = *commandTimeout
+			opts.Commands = commands
+		}
+	}
+
+	archive, err := gather.Create(path, root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("📦 Gathering namespace %s\n", namespace)
+	if err := archive.Namespace(ctx, clientset, namespace); err != nil {
+		archive.Close()
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, name := range podNames {
+		fmt.Printf("📦 Gathering pod %s/%s\n", namespace, name)
+		if err := archive.Pod(ctx, clientset, namespace, name, opts); err != nil {
+			archive.Close()
+			fmt.Fprintf(os.Stderr, "Error gathering pod %s: %v\n", name, err)
+			os.Exit(1)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing archive: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(podNames) == 0 {
+		fmt.Printf("✅ No failing pods in namespace %s; the archive holds its pods and events only\n", namespace)
+	}
+	if problems := archive.Problems(); len(problems) > 0 {
+		fmt.Printf("⚠️  %d item(s) could not be collected, see problems.txt\n", len(problems))
+	}
+	fmt.Printf("📦 Wrote %s (%d files)\n", path, archive.Files())
+}
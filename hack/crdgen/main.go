@@ -0,0 +1,150 @@
+// crdgen writes the PodDiagnosis CustomResourceDefinition, deriving the
+// OpenAPI schema from the Go types in pkg/apis/detective/v1alpha1.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/apis/detective/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+var timeType = reflect.TypeOf(metav1.Time{})
+
+// enums restricts string fields, keyed by "<struct>.<json name>"
+var enums = map[string][]string{
+	"PodDiagnosisStatus.phase": {v1alpha1.PhaseActive, v1alpha1.PhaseResolved},
+}
+
+func main() {
+	output := flag.String("o", "", "output file (default stdout)")
+	flag.Parse()
+
+	spec := schemaFor(reflect.TypeOf(v1alpha1.PodDiagnosisSpec{}))
+	status := schemaFor(reflect.TypeOf(v1alpha1.PodDiagnosisStatus{}))
+
+	crd := map[string]any{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata": map[string]any{
+			"name": v1alpha1.Resource.Resource + "." + v1alpha1.GroupVersion.Group,
+		},
+		"spec": map[string]any{
+			"group": v1alpha1.GroupVersion.Group,
+			"scope": "Namespaced",
+			"names": map[string]any{
+				"kind":       "PodDiagnosis",
+				"listKind":   "PodDiagnosisList",
+				"plural":     v1alpha1.Resource.Resource,
+				"singular":   "poddiagnosis",
+				"shortNames": []string{"pdiag"},
+			},
+			"versions": []any{map[string]any{
+				"name":    v1alpha1.GroupVersion.Version,
+				"served":  true,
+				"storage": true,
+				"subresources": map[string]any{
+					"status": map[string]any{},
+				},
+				"additionalPrinterColumns": []any{
+					column("Pod", "string", ".spec.podName"),
+					column("Container", "string", ".spec.containerName"),
+					column("Reason", "string", ".status.reason"),
+					column("Exit", "integer", ".status.exitCode"),
+					column("Phase", "string", ".status.phase"),
+					column("Last Seen", "date", ".status.lastSeen"),
+				},
+				"schema": map[string]any{
+					"openAPIV3Schema": map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"apiVersion": map[string]any{"type": "string"},
+							"kind":       map[string]any{"type": "string"},
+							"metadata":   map[string]any{"type": "object"},
+							"spec":       spec,
+							"status":     status,
+						},
+						"required": []string{"spec"},
+					},
+				},
+			}},
+		},
+	}
+
+	data, err := yaml.Marshal(crd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding CRD: %v\n", err)
+		os.Exit(1)
+	}
+	data = append([]byte("# Code generated by hack/crdgen. DO NOT EDIT.\n---\n"), data...)
+
+	if *output == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(*output, data, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing CRD: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func column(name, typ, path string) map[string]any {
+	return map[string]any{"name": name, "type": typ, "jsonPath": path}
+}
+
+// schemaFor builds the structural schema of a type from its json tags
+func schemaFor(t reflect.Type) map[string]any {
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int32:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case reflect.Int64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaFor(t.Elem())}
+	case reflect.Struct:
+		properties := map[string]any{}
+		var required []string
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+
+			prop := schemaFor(field.Type)
+			if values, ok := enums[t.Name()+"."+name]; ok {
+				prop["enum"] = values
+			}
+			properties[name] = prop
+
+			if !strings.Contains(opts, "omitempty") {
+				required = append(required, name)
+			}
+		}
+
+		schema := map[string]any{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	}
+
+	panic(fmt.Sprintf("crdgen: unsupported type %s", t))
+}
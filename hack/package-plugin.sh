@@ -0,0 +1,27 @@
+#!/usr/bin/env bash
+# Builds the release archives referenced by .krew.yaml into dist/.
+#
+#   hack/package-plugin.sh v0.4.0
+set -euo pipefail
+
+version="${1:?usage: $0 VERSION}"
+root="$(cd "$(dirname "$0")/.." && pwd)"
+dist="${root}/dist"
+mkdir -p "${dist}"
+
+for platform in linux/amd64 linux/arm64 darwin/amd64 darwin/arm64 windows/amd64; do
+  os="${platform%/*}"
+  arch="${platform#*/}"
+  bin="kubectl-detective"
+  [[ "${os}" == "windows" ]] && bin="${bin}.exe"
+
+  work="$(mktemp -d)"
+  CGO_ENABLED=0 GOOS="${os}" GOARCH="${arch}" go build -C "${root}" \
+    -trimpath -ldflags "-s -w -X main.version=${version}" -o "${work}/${bin}" .
+  [[ -f "${root}/LICENSE" ]] && cp "${root}/LICENSE" "${work}/"
+
+  archive="${dist}/kubectl-detective_${version}_${os}_${arch}.tar.gz"
+  tar -C "${work}" -czf "${archive}" .
+  rm -rf "${work}"
+  echo "${archive}"
+done
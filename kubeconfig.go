@@ -0,0 +1,168 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/leader"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// kubeFlags are the kubectl-compatible connection flags
+type kubeFlags struct {
+	namespace        string
+	kubeconfig       string
+	context          string
+	contexts         stringSlice
+	cluster          string
+	user             string
+	as               string
+	asGroups         stringSlice
+	preferKubeconfig bool
+}
+
+func (k *kubeFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&k.namespace, "namespace", "", "(optional) Kubernetes namespace (default: the kubeconfig context's namespace, or 'default')")
+	fs.StringVar(&k.namespace, "n", "", "shorthand for --namespace")
+	fs.StringVar(&k.kubeconfig, "kubeconfig", "", "(optional) path to the kubeconfig file (default $KUBECONFIG or ~/.kube/config)")
+	fs.Var(&k.contexts, "context", "(optional) kubeconfig context to use (repeat to watch several clusters)")
+	fs.StringVar(&k.cluster, "cluster", "", "(optional) kubeconfig cluster to use")
+	fs.StringVar(&k.user, "user", "", "(optional) kubeconfig user to use")
+	fs.StringVar(&k.as, "as", "", "(optional) username to impersonate")
+	fs.Var(&k.asGroups, "as-group", "(optional) group to impersonate (repeatable)")
+	fs.BoolVar(&k.preferKubeconfig, "prefer-kubeconfig", false, "use the kubeconfig even when running in a cluster")
+}
+
+// explicit reports whether the user asked for something only a kubeconfig provides
+func (k *kubeFlags) explicit() bool {
+	return k.kubeconfig != "" || k.context != "" || len(k.contexts) > 0 || k.cluster != "" || k.user != ""
+}
+
+// buildConfig creates Kubernetes config from kubeconfig file or in-cluster config
+func buildConfig(kube kubeFlags) (*rest.Config, error) {
+	config, err := loadConfig(kube)
+	if err != nil {
+		return nil, err
+	}
+
+	// Impersonation applies to in-cluster credentials too
+	if kube.as != "" || len(kube.asGroups) > 0 {
+		config.Impersonate = rest.ImpersonationConfig{
+			UserName: kube.as,
+			Groups:   kube.asGroups,
+		}
+	}
+
+	return config, nil
+}
+
+func loadConfig(kube kubeFlags) (*rest.Config, error) {
+	// Try in-cluster config first, unless a kubeconfig was asked for
+	if kube.inCluster() {
+		if config, err := rest.InClusterConfig(); err == nil {
+			slog.Info("Using in-cluster configuration")
+			return config, nil
+		}
+	}
+
+	kubeConfig := clientConfig(kube)
+
+	rawConfig, err := kubeConfig.RawConfig()
+	if err == nil {
+		contextName := kube.currentContext()
+		if contextName == "" {
+			contextName = rawConfig.CurrentContext
+		}
+		slog.Info("Using kubeconfig file", "context", contextName)
+	}
+
+	return kubeConfig.ClientConfig()
+}
+
+// resolveNamespace is --namespace, else the namespace the detector runs in when
+// in-cluster, else the kubeconfig context's namespace like kubectl, else "default"
+func resolveNamespace(kube kubeFlags) string {
+	if kube.namespace != "" {
+		return kube.namespace
+	}
+	if kube.inCluster() {
+		if _, err := rest.InClusterConfig(); err == nil {
+			return leader.DefaultNamespace()
+		}
+	}
+	if namespace, _, err := clientConfig(kube).Namespace(); err == nil && namespace != "" {
+		return namespace
+	}
+	return "default"
+}
+
+// currentContext is the context to use when only one cluster is involved
+func (k *kubeFlags) currentContext() string {
+	if k.context == "" && len(k.contexts) > 0 {
+		return k.contexts[0]
+	}
+	return k.context
+}
+
+// kubectlArgs passes the same cluster, user and impersonation to kubectl
+func (k *kubeFlags) kubectlArgs() []string {
+	var args []string
+	for _, flag := range []struct{ name, value string }{
+		{"--kubeconfig", k.kubeconfig},
+		{"--context", k.currentContext()},
+		{"--cluster", k.cluster},
+		{"--user", k.user},
+		{"--as", k.as},
+	} {
+		if flag.value != "" {
+			args = append(args, flag.name+"="+flag.value)
+		}
+	}
+	for _, group := range k.asGroups {
+		args = append(args, "--as-group="+group)
+	}
+	return args
+}
+
+func (k *kubeFlags) inCluster() bool {
+	return !k.preferKubeconfig && !k.explicit()
+}
+
+// clientConfig uses the same loading rules as kubectl: --kubeconfig, then
+// $KUBECONFIG, then ~/.kube/config
+func clientConfig(kube kubeFlags) clientcmd.ClientConfig {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kube.kubeconfig
+
+	configOverrides := &clientcmd.ConfigOverrides{
+		CurrentContext: kube.currentContext(),
+	}
+	configOverrides.Context.Cluster = kube.cluster
+	configOverrides.Context.AuthInfo = kube.user
+
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		loadingRules,
+		configOverrides,
+	)
+}
+
+// newClientset connects to the single cluster the flags select
+func newClientset(kube kubeFlags) *kubernetes.Clientset {
+	config, err := buildConfig(kube)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error building config: %v\n", err)
+		os.Exit(1)
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating client: %v\n", err)
+		os.Exit(1)
+	}
+	return clientset
+}
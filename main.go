@@ -3,102 +3,169 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 
-	"github.com/Maniratnam557/k8s-pod-detective/pkg/detector"
-
-	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/homedir"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/logging"
 )
 
-func main() {
-	var kubeconfig *string
-
-	// Only offer kubeconfig flag when running outside cluster
-	if home := homedir.HomeDir(); home != "" {
-		kubeconfig = flag.String("kubeconfig",
-			filepath.Join(home, ".kube", "config"),
-			"(optional) absolute path to the kubeconfig file")
-	} else {
-		kubeconfig = flag.String("kubeconfig", "",
-			"absolute path to the kubeconfig file")
-	}
+// command is a subcommand of the CLI
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(args []string)
+}
 
-	namespace := flag.String("namespace", "default",
-		"Kubernetes namespace to monitor")
+var commands = []command{
+	{"watch", "watch [flags]", "Watch pods and explain failures as they happen (default)", func(args []string) { runWatch(args, false) }},
+	{"scan", "scan [-n NS[,NS...] | -A] [flags]", "Explain every failing pod once; exits 3 on failures (CI gate)", runScan},
+	{"diagnose", "diagnose POD [flags]", "Run every check against one pod and print a full report", runDiagnose},
+	{"tui", "tui [-n NS | -A] [flags]", "Browse failing pods in an interactive terminal dashboard", runTUI},
+	{"gather", "gather [POD...] [flags]", "Collect a must-gather archive for pods, or every failing pod", runGather},
+	{"explain", "explain -f FILE|- [flags]", "Explain failures in saved kubectl output, without a cluster", runExplain},
+	{"explain", "explain reason|exit-code|list", "Look up a failure reason or exit code, without a cluster", runExplain},
+	{"summary", "summary FILE... [flags]", "Summarize the incidents in watch --record files", runSummary},
+	{"replay", "replay FILE [flags]", "Replay a watch --record file through a fake cluster", runReplay},
+	{"rbac", "rbac [flags]", "Print the RBAC rules the watch flags need", func(args []string) { runWatch(args, true) }},
+	{"version", "version", "Print the version", runVersion},
+}
 
-	podName := flag.String("pod", "", "(optional) specific pod name to monitor (e.g., 'nginx-abc123')")
+func main() {
+	args := os.Args[1:]
 
-	labelSelector := flag.String("labels", "", "(optional) label selector (e.g., 'app=nginx,tier=frontend')")
+	// Without a subcommand, watch like earlier releases did
+	if len(args) == 0 || (strings.HasPrefix(args[0], "-") && args[0] != "-h" && args[0] != "--help") {
+		runWatch(args, false)
+		return
+	}
 
-	flag.Parse()
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(os.Stdout)
+		return
+	}
 
-	// ===== BUILD CONFIG (WORKS BOTH IN-CLUSTER AND OUT-OF-CLUSTER) =====
-	config, err := buildConfig(*kubeconfig)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error building config: %v\n", err)
-		os.Exit(1)
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			cmd.run(args[1:])
+			return
+		}
 	}
 
-	// Debug output
-	fmt.Printf("[DEBUG] ==========================================\n")
-	fmt.Printf("[DEBUG] API Server URL: %s\n", config.Host)
-	fmt.Printf("[DEBUG] ==========================================\n\n")
+	fmt.Fprintf(os.Stderr, "Error: unknown command %q\n\n", args[0])
+	usage(os.Stderr)
+	os.Exit(1)
+}
 
-	// Create clientset
-	clientset, err := kubernetes.NewForConfig(config)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating client: %v\n", err)
-		os.Exit(1)
+// programName is how the user invoked us: "kubectl detective" when installed
+// as a kubectl plugin
+func programName() string {
+	name := strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe")
+	if plugin, ok := strings.CutPrefix(name, "kubectl-"); ok {
+		return "kubectl " + strings.ReplaceAll(plugin, "_", "-")
 	}
+	return name
+}
 
-	// Print banner
-	printBanner()
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "Detect and explain Kubernetes pod failures.\n\nUsage:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %s %-28s %s\n", programName(), cmd.usage, cmd.summary)
+	}
+	fmt.Fprintf(w, "\nRun '%s COMMAND -h' for the flags of a command.\n", programName())
+}
 
-	if *podName != "" && *labelSelector != "" {
-		fmt.Fprintf(os.Stderr, "Error: Cannot use both --pod and --labels together\n")
-		os.Exit(1)
+// newFlagSet creates a subcommand's flags with usage that names the command
+func newFlagSet(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s\n\nFlags:\n", programName(), usage)
+		fs.PrintDefaults()
 	}
+	return fs
+}
 
-	opts := detector.Options{
-		PodName:       *podName,
-		LabelSelector: *labelSelector,
+// parseInterspersed parses flags that may follow positional arguments, as in
+// 'diagnose my-pod -n prod', and returns the positional arguments
+func parseInterspersed(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		fs.Parse(args)
+		if fs.NArg() == 0 {
+			return positional
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
 	}
+}
+
+// logFlags are the logging flags every command takes
+type logFlags struct {
+	verbosity int
+	format    string
+}
+
+func (l *logFlags) register(fs *flag.FlagSet) {
+	fs.IntVar(&l.verbosity, "v", 0, "log verbosity: 0 logs info and above, 1 adds debug messages, higher is more verbose")
+	fs.StringVar(&l.format, "log-format", "text", "log format: text or json (logs go to stderr)")
+}
 
-	// Start detector
-	podDetector := detector.New(clientset, opts)
-	if err := podDetector.WatchPods(*namespace); err != nil {
-		fmt.Fprintf(os.Stderr, "Error watching pods: %v\n", err)
+func (l *logFlags) setup() {
+	if err := logging.Setup(os.Stderr, l.verbosity, l.format); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
-// buildConfig creates Kubernetes config from kubeconfig file or in-cluster config
-func buildConfig(kubeconfigPath string) (*rest.Config, error) {
-	// Try in-cluster config first
-	if config, err := rest.InClusterConfig(); err == nil {
-		fmt.Println("[INFO] Using in-cluster configuration")
-		return config, nil
+// serveHTTP runs an HTTP server for the life of the process
+func serveHTTP(name, addr string, handler http.Handler) {
+	slog.Info("Serving HTTP", "server", name, "addr", addr)
+	if err := http.ListenAndServe(addr, handler); err != nil {
+		fmt.Fprintf(os.Stderr, "Error serving %s: %v\n", name, err)
+		os.Exit(1)
 	}
+}
 
-	// Use kubeconfig - DON'T set ExplicitPath, let it find the file
-	fmt.Printf("[INFO] Using kubeconfig file\n")
+// stringSlice is a repeatable string flag
+type stringSlice []string
 
-	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
-	// DON'T SET ExplicitPath - let it use default discovery
-	// loadingRules.ExplicitPath = kubeconfigPath  ← REMOVE THIS!
+func (s *stringSlice) String() string {
+	return strings.Join(*s, ",")
+}
 
-	configOverrides := &clientcmd.ConfigOverrides{}
+func (s *stringSlice) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
 
-	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-		loadingRules,
-		configOverrides,
-	)
+// parseHeaders turns 'Name: value' flag values into a header map
+func parseHeaders(values []string) (map[string]string, error) {
+	headers := make(map[string]string)
+	for _, value := range values {
+		name, val, ok := strings.Cut(value, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid header %q, expected 'Name: value'", value)
+		}
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(val)
+	}
+	return headers, nil
+}
 
-	return kubeConfig.ClientConfig()
+// parseLabels turns 'name=value' flag values into a label map
+func parseLabels(values []string) (map[string]string, error) {
+	labels := make(map[string]string)
+	for _, value := range values {
+		name, val, ok := strings.Cut(value, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid label %q, expected 'name=value'", value)
+		}
+		labels[name] = val
+	}
+	return labels, nil
 }
 
 func printBanner() {
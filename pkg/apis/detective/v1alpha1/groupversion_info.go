@@ -0,0 +1,15 @@
+// Package v1alpha1 contains the PodDiagnosis API written by the detector.
+// The CRD manifest in config/crd is generated from these types.
+package v1alpha1
+
+//go:generate go run ../../../../hack/crdgen -o ../../../../config/crd/poddetective.io_poddiagnoses.yaml
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupVersion is the API group and version of the PodDiagnosis resource
+var GroupVersion = schema.GroupVersion{Group: "poddetective.io", Version: "v1alpha1"}
+
+// Resource is the PodDiagnosis resource, as used by the dynamic client
+var Resource = GroupVersion.WithResource("poddiagnoses")
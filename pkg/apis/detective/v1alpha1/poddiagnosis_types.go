@@ -0,0 +1,60 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Phases of a PodDiagnosis
+const (
+	PhaseActive   = "Active"
+	PhaseResolved = "Resolved"
+)
+
+// PodDiagnosisSpec identifies the failing container
+type PodDiagnosisSpec struct {
+	PodName       string `json:"podName"`
+	ContainerName string `json:"containerName"`
+
+	// Workload is the pod's top-level controller, e.g. "Deployment/web"
+	Workload string `json:"workload,omitempty"`
+}
+
+// PodDiagnosisStatus holds the detector's diagnosis of the failure
+type PodDiagnosisStatus struct {
+	// Phase is Active while the failure persists and Resolved once it is gone
+	Phase string `json:"phase"`
+
+	Reason   string `json:"reason"`
+	Message  string `json:"message,omitempty"`
+	ExitCode int32  `json:"exitCode,omitempty"`
+
+	// Summary is a one-line description of the failure
+	Summary string `json:"summary"`
+
+	// Evidence lists what the diagnosis is based on: messages, exit code
+	// meaning and the last log lines
+	Evidence []string `json:"evidence,omitempty"`
+
+	// SuggestedCommands are the kubectl commands that help investigate
+	SuggestedCommands []string `json:"suggestedCommands,omitempty"`
+
+	FirstSeen metav1.Time `json:"firstSeen"`
+	LastSeen  metav1.Time `json:"lastSeen"`
+}
+
+// PodDiagnosis is the detector's diagnosis of one failing pod container.
+// It is owned by the pod and garbage-collected with it.
+type PodDiagnosis struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PodDiagnosisSpec   `json:"spec"`
+	Status PodDiagnosisStatus `json:"status,omitempty"`
+}
+
+// PodDiagnosisList is a list of PodDiagnosis resources
+type PodDiagnosisList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PodDiagnosis `json:"items"`
+}
@@ -3,110 +3,555 @@ package detector
 import (
 	"context"
 	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Maniratnam557/k8s-pod-detective/pkg/explainer"
-
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/metrics"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/notifier"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/poddiagnosis"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 )
 
+// tracer records the detector's own work (listing, log fetches, explaining
+// and notifying). It is a no-op unless a tracer provider is installed.
+var tracer = otel.Tracer("github.com/Maniratnam557/k8s-pod-detective/pkg/detector")
+
 type PodDetector struct {
-	clientset *kubernetes.Clientset
-	seen      map[string]bool
+	clientset kubernetes.Interface
+	mu        sync.Mutex // held for a whole poll, and to report on becoming leader
+	seen      map[string]*incident
+	active    int // len(seen) as last added to metrics.ActiveIncidents
 	options   Options
+	lastSync  atomic.Int64 // unix nanoseconds of the last completed poll
+	leading   atomic.Bool
+
+	// replaced holds evicted pods whose incident ended, until they are
+	// deleted, so they aren't reported again
+	replaced map[types.UID]bool
+}
+
+// incident is a failure that has been reported and not yet seen to recover
+type incident struct {
+	info      explainer.FailureInfo
+	firstSeen time.Time
+	startedAt time.Time // when the failure began, as far as the pod status tells
+	podUID    types.UID
+	restarts  int32 // of the failing container, as last seen
+	missing   int   // polls since the pod and every pod of its workload disappeared
+	reported  bool  // false until this replica or the leader before it reported it
+
+	// node is set on a node's mass eviction incident, and group on the
+	// incidents of the pods it evicted, which recover quietly
+	node  string
+	group string
 }
 
 type Options struct {
 	PodName       string
 	LabelSelector string
+
+	// Cluster tags every failure when several clusters are watched (optional)
+	Cluster string
+
+	// Output receives explanations (default os.Stdout). Detectors sharing an
+	// output must get a writer that is safe for concurrent use.
+	Output io.Writer
+
+	// Interval between polls (default 10s)
+	Interval time.Duration
+
+	// Clock tells the time failures are seen and recover at (default
+	// time.Now). Replay sets it to the recorded time of each poll.
+	Clock func() time.Time
+
+	// LeaderElection starts the detector as a follower: it keeps tracking
+	// failures but only reports them while SetLeading(true)
+	LeaderElection bool
+
+	// Notifier receives failure and recovery events (optional)
+	Notifier notifier.Notifier
+
+	// Recorder records a Warning Event with the diagnosis on failing pods (optional)
+	Recorder record.EventRecorder
+
+	// Diagnoses writes a PodDiagnosis resource per incident (optional)
+	Diagnoses *poddiagnosis.Writer
+
+	// Logs fetches container logs (default: the pod log API)
+	Logs LogSource
+
+	// Observer sees everything the detector reads and reports, e.g. to
+	// record it for replay (optional)
+	Observer Observer
 }
 
-func New(clientset *kubernetes.Clientset, opts Options) *PodDetector {
-	return &PodDetector{
+// Observer sees the cluster state the detector reads and the events it emits
+type Observer interface {
+	ObservePods(namespace string, pods *corev1.PodList)
+	ObserveLog(namespace, podName, containerName, log string, err error)
+	// ObserveObject sees the ReplicaSets and Jobs read to resolve workloads,
+	// and the Events read to explain probe failures
+	ObserveObject(object runtime.Object)
+	ObserveEvent(event notifier.Event)
+}
+
+// DefaultInterval is the poll interval used when Options.Interval is unset
+const DefaultInterval = 10 * time.Second
+
+func New(clientset kubernetes.Interface, opts Options) *PodDetector {
+	if opts.Interval == 0 {
+		opts.Interval = DefaultInterval
+	}
+	if opts.Output == nil {
+		opts.Output = os.Stdout
+	}
+	if opts.Logs == nil {
+		opts.Logs = apiLogs{clientset: clientset}
+	}
+	if opts.Clock == nil {
+		opts.Clock = time.Now
+	}
+
+	d := &PodDetector{
 		clientset: clientset,
-		seen:      make(map[string]bool),
+		seen:      make(map[string]*incident),
+		replaced:  make(map[types.UID]bool),
 		options:   opts,
 	}
+	d.leading.Store(!opts.LeaderElection)
+	return d
 }
 
-// WatchPods monitors pods for failures
-func (d *PodDetector) WatchPods(namespace string) error {
-	fmt.Printf("🔍 Watching pods in namespace: %s\n\n", namespace)
+// SetLeading switches reporting on or off. Followers keep their view of the
+// cluster up to date so a new leader doesn't re-report existing failures.
+// Those first seen after handover, when the previous leader last renewed its
+// Lease, it may never have reported, so a new leader reports them; see
+// leader.Run.
+func (d *PodDetector) SetLeading(leading bool, handover time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.leading.Store(leading)
+	if leading {
+		d.reportPending(context.Background(), handover)
+	}
+}
 
-	for {
+// reportPending reports the incidents found while following that the
+// previous leader didn't see
+func (d *PodDetector) reportPending(ctx context.Context, handover time.Time) {
+	for _, key := range slices.Sorted(maps.Keys(d.seen)) {
+		inc := d.seen[key]
+		if inc.reported {
+			continue
+		}
+		if !inc.firstSeen.After(handover) {
+			inc.reported = true
+			continue
+		}
+		switch {
+		case inc.node != "":
+			d.announceNode(ctx, inc)
+		case inc.group != "":
+			d.announceMember(podRef(inc), inc, d.seen[inc.group])
+		default:
+			d.announce(ctx, podRef(inc), inc)
+		}
+	}
+}
+
+// podRef stands in for the pod of an incident reported after it was found,
+// to record the Event and PodDiagnosis on
+func podRef(inc *incident) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      inc.info.PodName,
+		Namespace: inc.info.Namespace,
+		UID:       inc.podUID,
+	}}
+}
+
+// now is the detector's clock, see Options.Clock
+func (d *PodDetector) now() time.Time {
+	return d.options.Clock()
+}
 
-		listOptions := metav1.ListOptions{}
+// LastSync returns when the last poll completed, zero before the first one
+func (d *PodDetector) LastSync() time.Time {
+	if nanos := d.lastSync.Load(); nanos != 0 {
+		return time.Unix(0, nanos)
+	}
+	return time.Time{}
+}
+
+// WatchPods monitors pods for failures until ctx is cancelled. Only a failure
+// of the very first list is returned; later ones are logged and retried.
+func (d *PodDetector) WatchPods(ctx context.Context, namespace string) error {
+	if d.options.Cluster != "" {
+		fmt.Fprintf(d.options.Output, "🔍 Watching pods in namespace: %s (cluster %s)\n\n", namespace, d.options.Cluster)
+	} else {
+		fmt.Fprintf(d.options.Output, "🔍 Watching pods in namespace: %s\n\n", namespace)
+	}
 
-		if d.options.LabelSelector != "" {
-			listOptions.LabelSelector = d.options.LabelSelector
+	ticker := time.NewTicker(d.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		slog.Debug("Querying pods", "namespace", namespace)
+
+		if err := d.poll(ctx, namespace, d.listOptions()); err != nil {
+			metrics.ListErrors.Inc()
+			if d.LastSync().IsZero() {
+				return fmt.Errorf("failed to list pods: %w", err)
+			}
+			slog.Error("Failed to list pods, retrying", "cluster", d.options.Cluster, "namespace", namespace, "error", err)
 		}
 
-		if d.options.PodName != "" {
-			listOptions.FieldSelector = fmt.Sprintf("metadata.name=%s", d.options.PodName)
+		// Wait before next check
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
 		}
+	}
+}
 
-		fmt.Printf("[DEBUG] Querying namespace='%s'\n", namespace)
+// Poll lists the pods once and reports new failures and recoveries, as each
+// WatchPods tick does
+func (d *PodDetector) Poll(ctx context.Context, namespace string) error {
+	return d.poll(ctx, namespace, d.listOptions())
+}
 
-		pods, err := d.clientset.CoreV1().Pods(namespace).List(
-			context.TODO(),
-			listOptions,
-		)
+// poll lists the pods once and reports new failures and recoveries
+func (d *PodDetector) poll(ctx context.Context, namespace string, listOptions metav1.ListOptions) error {
+	ctx, span := tracer.Start(ctx, "detector.poll",
+		trace.WithAttributes(attribute.String("k8s.namespace.name", namespace)))
+	defer span.End()
 
-		if err != nil {
-			return fmt.Errorf("failed to list pods: %w", err)
-		}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	pods, err := d.listPods(ctx, namespace, listOptions)
+	if err != nil {
+		return err
+	}
+	if d.options.Observer != nil {
+		d.options.Observer.ObservePods(namespace, pods)
+	}
+
+	d.forgetDeleted(pods)
+
+	// Check each pod; new evictions are reported last, grouped by node
+	observed := make(map[string]bool)
+	var evictions []eviction
+	for _, pod := range pods.Items {
+		evictions = append(evictions, d.checkPod(ctx, &pod, observed)...)
+	}
+	d.reportEvictions(ctx, evictions)
+
+	d.resolveRecovered(ctx, pods, observed)
+
+	// Detectors for other clusters share the gauge, so only apply our change
+	metrics.ActiveIncidents.Add(float64(len(d.seen) - d.active))
+	d.active = len(d.seen)
+	d.lastSync.Store(time.Now().UnixNano())
+
+	return nil
+}
+
+func (d *PodDetector) listPods(ctx context.Context, namespace string, listOptions metav1.ListOptions) (*corev1.PodList, error) {
+	ctx, span := tracer.Start(ctx, "pods.list")
+	defer span.End()
+
+	pods, err := d.clientset.CoreV1().Pods(namespace).List(ctx, listOptions)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "list failed")
+		return nil, err
+	}
 
-		// Check each pod
-		for _, pod := range pods.Items {
-			d.checkPod(&pod)
+	span.SetAttributes(attribute.Int("pod_detective.pods", len(pods.Items)))
+	return pods, nil
+}
+
+// checkPod reports the new failures of a pod, except evictions, which it
+// returns for reportEvictions
+func (d *PodDetector) checkPod(ctx context.Context, pod *corev1.Pod, observed map[string]bool) []eviction {
+	var evictions []eviction
+	for _, f := range d.failures(pod) {
+		// An evicted pod stays around until it is deleted, so its incident
+		// ends when a replacement is healthy, see recovery
+		if f.evicted && d.replaced[pod.UID] {
+			continue
+		}
+		if !f.evicted {
+			observed[f.key] = true
 		}
 
-		// Wait before next check
-		time.Sleep(10 * time.Second)
+		inc, ok := d.seen[f.key]
+		switch {
+		case ok:
+			inc.podUID = pod.UID
+			inc.restarts = f.status.RestartCount
+			if inc.group == "" {
+				d.writeDiagnosis(ctx, pod, inc)
+			}
+		case f.evicted:
+			evictions = append(evictions, eviction{pod: pod, failure: f})
+		default:
+			d.report(ctx, pod, f, d.gather(ctx, pod, f))
+		}
 	}
+	return evictions
+}
+
+// failure is a failing container found in a pod's status
+type failure struct {
+	key        string // unique per pod, container and reason to avoid duplicate reports
+	status     corev1.ContainerStatus
+	waiting    *corev1.ContainerStateWaiting
+	terminated *corev1.ContainerStateTerminated
+	unready    bool // running but failing its readiness probe
+	evicted    bool // the whole pod was evicted
+	failedAt   time.Time
 }
 
-func (d *PodDetector) checkPod(pod *corev1.Pod) {
+// failures lists the failing containers of a pod without any API calls
+func (d *PodDetector) failures(pod *corev1.Pod) []failure {
 	podKey := fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
 
-	// Check container statuses
+	// The containers of an evicted pod were stopped by the kubelet; their
+	// exit codes say nothing about the pod
+	if evicted(pod) {
+		return []failure{{key: podKey + "-Evicted", evicted: true, failedAt: evictedAt(pod)}}
+	}
+
+	var found []failure
 	for _, containerStatus := range pod.Status.ContainerStatuses {
-		if containerStatus.State.Waiting != nil {
-			waiting := containerStatus.State.Waiting
-
-			// Detect failure reasons
-			if d.isFailureReason(waiting.Reason) {
-				// Create unique key to avoid duplicate reports
-				statusKey := fmt.Sprintf("%s-%s-%s", podKey, containerStatus.Name, waiting.Reason)
-
-				if !d.seen[statusKey] {
-					info := d.gatherFailureInfo(pod, containerStatus, waiting)
-					explanation := explainer.Explain(info)
-					fmt.Println(explanation)
-					fmt.Println("=====================================\n")
-					d.seen[statusKey] = true
-				}
-			}
+		// Detect failure reasons
+		if waiting := containerStatus.State.Waiting; waiting != nil && d.isFailureReason(waiting.Reason) {
+			found = append(found, failure{
+				key:      fmt.Sprintf("%s-%s-%s", podKey, containerStatus.Name, waiting.Reason),
+				status:   containerStatus,
+				waiting:  waiting,
+				failedAt: failureStart(pod, containerStatus),
+			})
 		}
 
 		// Check terminated state
-		if containerStatus.State.Terminated != nil {
-			terminated := containerStatus.State.Terminated
-			if terminated.ExitCode != 0 {
-				statusKey := fmt.Sprintf("%s-%s-terminated-%d", podKey, containerStatus.Name, terminated.ExitCode)
-
-				if !d.seen[statusKey] {
-					info := d.gatherTerminationInfo(pod, containerStatus, terminated)
-					explanation := explainer.Explain(info)
-					fmt.Println(explanation)
-					fmt.Println("=====================================\n")
-					d.seen[statusKey] = true
-				}
-			}
+		if terminated := containerStatus.State.Terminated; terminated != nil && terminated.ExitCode != 0 {
+			found = append(found, failure{
+				key:        fmt.Sprintf("%s-%s-terminated-%d", podKey, containerStatus.Name, terminated.ExitCode),
+				status:     containerStatus,
+				terminated: terminated,
+				failedAt:   terminated.FinishedAt.Time,
+			})
+		}
+
+		// Running but kept out of service by its readiness probe
+		if since, ok := unready(pod, containerStatus, d.now()); ok {
+			found = append(found, failure{
+				key:      fmt.Sprintf("%s-%s-%s", podKey, containerStatus.Name, explainer.ProbeReason("readiness")),
+				status:   containerStatus,
+				unready:  true,
+				failedAt: since,
+			})
 		}
 	}
+	return found
+}
+
+// gather collects the logs and workload needed to explain a failure
+func (d *PodDetector) gather(ctx context.Context, pod *corev1.Pod, f failure) explainer.FailureInfo {
+	switch {
+	case f.evicted:
+		return d.gatherEvictionInfo(ctx, pod, d.node(ctx, pod.Spec.NodeName))
+	case f.unready:
+		return d.gatherReadinessInfo(ctx, pod, f.status)
+	case f.waiting != nil:
+		return d.gatherFailureInfo(ctx, pod, f.status, f.waiting)
+	}
+	return d.gatherTerminationInfo(ctx, pod, f.status, f.terminated)
+}
+
+// report tracks a new failure and announces it
+func (d *PodDetector) report(ctx context.Context, pod *corev1.Pod, f failure, info explainer.FailureInfo) {
+	inc := &incident{
+		info:      info,
+		firstSeen: d.now(),
+		podUID:    pod.UID,
+		restarts:  f.status.RestartCount,
+	}
+	inc.startedAt = inc.firstSeen
+	if !f.failedAt.IsZero() && f.failedAt.Before(inc.firstSeen) {
+		inc.startedAt = f.failedAt
+	}
+	d.seen[f.key] = inc
+	d.announce(ctx, pod, inc)
+}
+
+// announce prints the explanation for an incident, records it on the pod and
+// notifies about it, once this replica leads
+func (d *PodDetector) announce(ctx context.Context, pod *corev1.Pod, inc *incident) {
+	if !d.leading.Load() {
+		return
+	}
+	inc.reported = true
+
+	info := inc.info
+	explanation := d.explain(ctx, info)
+	if info.ExitCode != 0 {
+		metrics.LastExitCode.WithLabelValues(info.Cluster, info.Namespace, info.PodName, info.ContainerName).Set(float64(info.ExitCode))
+	}
+	if inc.startedAt.Before(inc.firstSeen) {
+		metrics.TimeToDetect.Observe(d.now().Sub(inc.startedAt).Seconds())
+	}
+
+	d.recordEvent(pod, info)
+	d.writeDiagnosis(ctx, pod, inc)
+
+	d.notify(ctx, notifier.EventFailure, info, explanation, nil)
+}
+
+// explain prints the explanation of a new incident and counts it
+func (d *PodDetector) explain(ctx context.Context, info explainer.FailureInfo) string {
+	_, span := tracer.Start(ctx, "explain", trace.WithAttributes(attribute.String("pod_detective.reason", info.Reason)))
+	explanation := explainer.Explain(info)
+	span.End()
+
+	// One write per explanation so detectors sharing an output don't interleave
+	fmt.Fprint(d.options.Output, explanation+"\n=====================================\n\n")
+
+	metrics.FailuresTotal.WithLabelValues(info.Cluster, info.Namespace, info.Workload, info.Reason, info.ContainerName).Inc()
+	return explanation
+}
+
+// resolveRecovered reports every previously seen failure that was not
+// observed in the latest poll and has recovered as such, and forgets it so a
+// recurrence is reported again. Failures that are gone but whose pod isn't
+// healthy yet stay open; if they come back, it's the same incident.
+func (d *PodDetector) resolveRecovered(ctx context.Context, pods *corev1.PodList, observed map[string]bool) {
+	for statusKey, inc := range d.seen {
+		if observed[statusKey] || inc.node != "" {
+			continue
+		}
+
+		if recovery := d.recovery(ctx, inc, pods); recovery != nil {
+			d.resolve(ctx, statusKey, inc, recovery)
+		}
+	}
+
+	// A mass eviction is over once every pod it evicted was replaced
+	for key, inc := range d.seen {
+		if inc.node != "" && !d.members(key) {
+			recovery := &notifier.Recovery{StartedAt: inc.startedAt.UTC(), RecoveredAt: d.now().UTC()}
+			recovery.DurationSeconds = recovery.Duration().Seconds()
+			d.resolve(ctx, key, inc, recovery)
+		}
+	}
+}
+
+// resolve forgets an incident and reports how it ended. Pods of a mass
+// eviction end quietly; their node-level incident is reported instead.
+func (d *PodDetector) resolve(ctx context.Context, key string, inc *incident, recovery *notifier.Recovery) {
+	info := inc.info
+	delete(d.seen, key)
+	if info.Reason == "Evicted" && inc.node == "" {
+		d.replaced[inc.podUID] = true
+	}
+	metrics.LastExitCode.DeleteLabelValues(info.Cluster, info.Namespace, info.PodName, info.ContainerName)
+
+	if !d.leading.Load() || !inc.reported || inc.group != "" {
+		return
+	}
+
+	if recovery.Deleted {
+		fmt.Fprintf(d.options.Output, "🗑️  GONE: %s, %s\n\n", describe(inc), recovery)
+	} else {
+		metrics.TimeToRecover.Observe(recovery.Duration().Seconds())
+		fmt.Fprintf(d.options.Output, "✅ RECOVERED: %s, %s\n\n", describe(inc), recovery)
+	}
+
+	// Node-level incidents have no PodDiagnosis
+	if d.options.Diagnoses != nil && inc.node == "" {
+		if err := d.options.Diagnoses.Resolve(ctx, info, recovery.RecoveredAt); err != nil {
+			slog.Warn("Failed to resolve PodDiagnosis", "error", err)
+		}
+	}
+
+	d.notify(ctx, notifier.EventRecovery, info, "", recovery)
+}
+
+// writeDiagnosis creates or refreshes the PodDiagnosis resource of an incident
+func (d *PodDetector) writeDiagnosis(ctx context.Context, pod *corev1.Pod, inc *incident) {
+	if d.options.Diagnoses == nil || !d.leading.Load() {
+		return
+	}
+
+	if err := d.options.Diagnoses.Record(ctx, pod, inc.info, inc.firstSeen, d.now()); err != nil {
+		slog.Warn("Failed to record PodDiagnosis", "error", err)
+	}
+}
+
+func (d *PodDetector) notify(ctx context.Context, eventType notifier.EventType, info explainer.FailureInfo, diagnosis string, recovery *notifier.Recovery) {
+	event := notifier.Event{
+		Type:      eventType,
+		Time:      d.now().UTC(),
+		Failure:   info,
+		Diagnosis: diagnosis,
+		Recovery:  recovery,
+	}
+	if d.options.Observer != nil {
+		d.options.Observer.ObserveEvent(event)
+	}
+
+	if d.options.Notifier == nil {
+		return
+	}
+
+	ctx, span := tracer.Start(ctx, "notify", trace.WithAttributes(attribute.String("pod_detective.event", string(eventType))))
+	defer span.End()
+
+	if err := d.options.Notifier.Notify(ctx, event); err != nil {
+		span.RecordError(err)
+		slog.Warn("Failed to notify", "event", eventType,
+			"namespace", info.Namespace, "pod", info.PodName, "error", err)
+	}
+}
+
+func clusterPrefix(cluster string) string {
+	if cluster == "" {
+		return ""
+	}
+	return "[" + cluster + "] "
+}
+
+// failureStart estimates when a waiting container started failing: the end of
+// its last run if it has one, otherwise when the pod started
+func failureStart(pod *corev1.Pod, status corev1.ContainerStatus) time.Time {
+	if last := status.LastTerminationState.Terminated; last != nil {
+		return last.FinishedAt.Time
+	}
+	if pod.Status.StartTime != nil {
+		return pod.Status.StartTime.Time
+	}
+	return time.Time{}
 }
 
 func (d *PodDetector) isFailureReason(reason string) bool {
@@ -128,15 +573,16 @@ func (d *PodDetector) isFailureReason(reason string) bool {
 }
 
 func (d *PodDetector) gatherFailureInfo(
+	ctx context.Context,
 	pod *corev1.Pod,
 	status corev1.ContainerStatus,
 	waiting *corev1.ContainerStateWaiting,
 ) explainer.FailureInfo {
 
 	// Get last logs if available
-	lastLog := d.getLastLog(pod.Namespace, pod.Name, status.Name)
+	lastLog := d.getLastLog(ctx, pod.Namespace, pod.Name, status.Name)
 
-	return explainer.FailureInfo{
+	info := explainer.FailureInfo{
 		PodName:       pod.Name,
 		Namespace:     pod.Namespace,
 		ContainerName: status.Name,
@@ -144,10 +590,15 @@ func (d *PodDetector) gatherFailureInfo(
 		Message:       waiting.Message,
 		ExitCode:      0,
 		LastLog:       lastLog,
+		Workload:      d.Workload(ctx, pod),
+		Cluster:       d.options.Cluster,
 	}
+	d.blameProbe(ctx, pod, status, &info)
+	return info
 }
 
 func (d *PodDetector) gatherTerminationInfo(
+	ctx context.Context,
 	pod *corev1.Pod,
 	status corev1.ContainerStatus,
 	terminated *corev1.ContainerStateTerminated,
@@ -158,9 +609,9 @@ func (d *PodDetector) gatherTerminationInfo(
 		reason = "CrashLoopBackOff"
 	}
 
-	lastLog := d.getLastLog(pod.Namespace, pod.Name, status.Name)
+	lastLog := d.getLastLog(ctx, pod.Namespace, pod.Name, status.Name)
 
-	return explainer.FailureInfo{
+	info := explainer.FailureInfo{
 		PodName:       pod.Name,
 		Namespace:     pod.Namespace,
 		ContainerName: status.Name,
@@ -168,22 +619,53 @@ func (d *PodDetector) gatherTerminationInfo(
 		Message:       terminated.Message,
 		ExitCode:      terminated.ExitCode,
 		LastLog:       lastLog,
+		Workload:      d.Workload(ctx, pod),
+		Cluster:       d.options.Cluster,
 	}
+	d.blameProbe(ctx, pod, status, &info)
+	return info
 }
 
-func (d *PodDetector) getLastLog(namespace, podName, containerName string) string {
+func (d *PodDetector) getLastLog(ctx context.Context, namespace, podName, containerName string) string {
+	ctx, span := tracer.Start(ctx, "pods.log", trace.WithAttributes(attribute.String("k8s.container.name", containerName)))
+	defer span.End()
+
+	start := time.Now()
+	logs, err := d.options.Logs.LastLog(ctx, namespace, podName, containerName)
+	metrics.LogFetchDuration.Observe(time.Since(start).Seconds())
+	if d.options.Observer != nil {
+		d.options.Observer.ObserveLog(namespace, podName, containerName, logs, err)
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		return ""
+	}
+
+	return logs
+}
+
+// LogSource fetches the last lines a container logged
+type LogSource interface {
+	LastLog(ctx context.Context, namespace, podName, containerName string) (string, error)
+}
+
+// apiLogs reads logs through the pod log API
+type apiLogs struct {
+	clientset kubernetes.Interface
+}
+
+func (l apiLogs) LastLog(ctx context.Context, namespace, podName, containerName string) (string, error) {
 	tailLines := int64(10)
 	logOptions := &corev1.PodLogOptions{
 		Container: containerName,
 		TailLines: &tailLines,
 	}
 
-	logs, err := d.clientset.CoreV1().Pods(namespace).
-		GetLogs(podName, logOptions).Do(context.TODO()).Raw()
-
+	logs, err := l.clientset.CoreV1().Pods(namespace).
+		GetLogs(podName, logOptions).Do(ctx).Raw()
 	if err != nil {
-		return ""
+		return "", err
 	}
-
-	return string(logs)
+	return string(logs), nil
 }
@@ -0,0 +1,210 @@
+package detector
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/leader"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/metrics"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/notifier"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type events []notifier.Event
+
+func (e *events) Notify(ctx context.Context, event notifier.Event) error {
+	*e = append(*e, event)
+	return nil
+}
+
+func crashingPod(name string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID("uid-" + name)},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "app",
+				RestartCount: 4,
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+					Reason:  "CrashLoopBackOff",
+					Message: "back-off 1m20s restarting failed container",
+				}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}},
+			}},
+		},
+	}
+}
+
+// replica is one detector campaigning for the Lease, as a watch pod does
+type replica struct {
+	detector *PodDetector
+	out      bytes.Buffer
+	sent     events
+	leading  chan bool
+	stop     context.CancelFunc
+	stopped  chan struct{}
+}
+
+func startReplica(t *testing.T, clientset *fake.Clientset, identity string) *replica {
+	t.Helper()
+	r := &replica{leading: make(chan bool, 4), stopped: make(chan struct{})}
+	r.detector = New(clientset, Options{Output: &r.out, LeaderElection: true, Notifier: &r.sent})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.stop = cancel
+	go func() {
+		defer close(r.stopped)
+		err := leader.Run(ctx, clientset, leader.Config{
+			Namespace:     "default",
+			LeaseName:     "k8s-pod-detective",
+			Identity:      identity,
+			LeaseDuration: 600 * time.Millisecond,
+			RenewDeadline: 400 * time.Millisecond,
+			RetryPeriod:   100 * time.Millisecond,
+		}, func(leading bool, handover time.Time) {
+			r.detector.SetLeading(leading, handover)
+			r.leading <- leading
+		})
+		if err != nil {
+			t.Error(err)
+		}
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-r.stopped
+	})
+	return r
+}
+
+func (r *replica) waitLeading(t *testing.T) {
+	t.Helper()
+	select {
+	case leading := <-r.leading:
+		if !leading {
+			t.Fatal("lost the lease instead of gaining it")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("never became leader")
+	}
+}
+
+// failuresSent counts the failure events sent for a pod
+func failuresSent(sent events, pod string) int {
+	n := 0
+	for _, event := range sent {
+		if event.Type == notifier.EventFailure && event.Failure.PodName == pod {
+			n++
+		}
+	}
+	return n
+}
+
+func TestFailoverReportsEachFailureOnce(t *testing.T) {
+	clientset := fake.NewClientset(crashingPod("api"))
+
+	first := startReplica(t, clientset, "detective-0")
+	first.waitLeading(t)
+	second := startReplica(t, clientset, "detective-1")
+
+	// Both see api; only the leader reports it
+	pollTimes(t, first.detector, 1)
+	pollTimes(t, second.detector, 1)
+
+	// The leader shuts down, releasing the Lease, and worker fails while the
+	// follower takes over
+	first.stop()
+	<-first.stopped
+	if _, err := clientset.CoreV1().Pods("default").Create(context.Background(), crashingPod("worker"), metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	pollTimes(t, second.detector, 1)
+
+	second.waitLeading(t)
+	pollTimes(t, second.detector, 2)
+
+	for _, pod := range []string{"api", "worker"} {
+		if got := failuresSent(first.sent, pod) + failuresSent(second.sent, pod); got != 1 {
+			t.Errorf("%s reported %d times across the failover, want once", pod, got)
+		}
+	}
+	if failuresSent(first.sent, "api") != 1 || failuresSent(second.sent, "worker") != 1 {
+		t.Errorf("first leader sent %+v, second sent %+v; want api from the first and worker from the second", first.sent, second.sent)
+	}
+	explanations := strings.Count(first.out.String(), "PROBLEM DETECTED") + strings.Count(second.out.String(), "PROBLEM DETECTED")
+	if explanations != 2 {
+		t.Errorf("printed %d explanations, want 2:\n%s%s", explanations, first.out.String(), second.out.String())
+	}
+}
+
+// lastExitCodes reads the pod_detective_last_exit_code series by cluster
+func lastExitCodes(t *testing.T, pod string) map[string]float64 {
+	t.Helper()
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	codes := make(map[string]float64)
+	for _, family := range families {
+		if family.GetName() != "pod_detective_last_exit_code" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["pod"] == pod {
+				codes[labels["cluster"]] = metric.GetGauge().GetValue()
+			}
+		}
+	}
+	return codes
+}
+
+func TestLastExitCodeSeparatesClusters(t *testing.T) {
+	east, west := crashingPod("shared"), crashingPod("shared")
+	east.Status.ContainerStatuses[0].State = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}}
+	west.Status.ContainerStatuses[0].State = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 2}}
+
+	eastClient := fake.NewClientset(east)
+	var out bytes.Buffer
+	detectors := []*PodDetector{
+		New(eastClient, Options{Output: &out, Cluster: "east"}),
+		New(fake.NewClientset(west), Options{Output: &out, Cluster: "west"}),
+	}
+	for _, d := range detectors {
+		pollTimes(t, d, 1)
+	}
+
+	if got := lastExitCodes(t, "shared"); got["east"] != 1 || got["west"] != 2 {
+		t.Fatalf("last exit codes = %v, want east 1 and west 2", got)
+	}
+
+	// Recovering in one cluster leaves the other cluster's series alone
+	east.Status.ContainerStatuses[0] = corev1.ContainerStatus{
+		Name:  "app",
+		Ready: true,
+		State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+	}
+	if _, err := eastClient.CoreV1().Pods("default").UpdateStatus(context.Background(), east, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	pollTimes(t, detectors[0], 1)
+
+	got := lastExitCodes(t, "shared")
+	if _, ok := got["east"]; ok {
+		t.Errorf("east series still exported after recovery: %v", got)
+	}
+	if got["west"] != 2 {
+		t.Errorf("west series = %v, want 2", got)
+	}
+}
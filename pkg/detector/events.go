@@ -0,0 +1,44 @@
+package detector
+
+import (
+	"fmt"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/explainer"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// EventReason is the reason of the Warning events recorded on failing pods
+const EventReason = "DetectiveDiagnosis"
+
+// NewEventRecorder returns a recorder that writes Events through the API
+// server. The broadcaster's correlator aggregates similar events, so a pod
+// that keeps failing the same way bumps the count of one event instead of
+// adding new ones. Call the returned function on shutdown to flush it.
+func NewEventRecorder(clientset kubernetes.Interface) (record.EventRecorder, func()) {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{
+		Interface: clientset.CoreV1().Events(""),
+	})
+
+	recorder := broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "pod-detective"})
+	return recorder, broadcaster.Shutdown
+}
+
+// recordEvent attaches a one-line diagnosis and the top fix step to the pod
+func (d *PodDetector) recordEvent(pod *corev1.Pod, info explainer.FailureInfo) {
+	if d.options.Recorder == nil {
+		return
+	}
+
+	message := explainer.Summary(info)
+	if fixes := explainer.FixSteps(info); len(fixes) > 0 {
+		message += fmt.Sprintf(". Suggested fix: %s", fixes[0])
+	}
+
+	d.options.Recorder.Event(pod, corev1.EventTypeWarning, EventReason, message)
+}
@@ -0,0 +1,369 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/explainer"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/metrics"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/notifier"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// A node that evicts massEviction pods within massEvictionWindow gets one
+// node-level incident instead of one per pod. The kubelet evicts about one
+// pod per housekeeping cycle, so a single poll rarely sees them all.
+const (
+	massEviction       = 3
+	massEvictionWindow = 10 * time.Minute
+)
+
+// evicted reports whether the kubelet evicted a pod. Evicted pods stay Failed
+// until they are deleted, whatever their containers say.
+func evicted(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodFailed && pod.Status.Reason == "Evicted"
+}
+
+// evictedAt estimates when a pod was evicted: when the kubelet marked it as
+// disrupted, otherwise when its last container stopped
+func evictedAt(pod *corev1.Pod) time.Time {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.DisruptionTarget && condition.Status == corev1.ConditionTrue {
+			return condition.LastTransitionTime.Time
+		}
+	}
+
+	var at time.Time
+	for _, status := range pod.Status.ContainerStatuses {
+		if terminated := status.State.Terminated; terminated != nil && terminated.FinishedAt.After(at) {
+			at = terminated.FinishedAt.Time
+		}
+	}
+	return at
+}
+
+// eviction is a newly found evicted pod, held back until the poll is done so
+// the evictions of a node can be grouped
+type eviction struct {
+	pod     *corev1.Pod
+	failure failure
+}
+
+// at is when the pod was evicted, or now if that's unknown
+func (e eviction) at(now time.Time) time.Time {
+	if e.failure.failedAt.IsZero() {
+		return now
+	}
+	return e.failure.failedAt
+}
+
+// gatherEvictionInfo explains an evicted pod from its eviction message, its
+// requests and the node's current pressure
+func (d *PodDetector) gatherEvictionInfo(ctx context.Context, pod *corev1.Pod, node *corev1.Node) explainer.FailureInfo {
+	details := explainer.ParseEviction(pod.Status.Message)
+	details.Node = pod.Spec.NodeName
+	details.QOSClass = string(pod.Status.QOSClass)
+	details.Conditions = pressure(node)
+	details.Containers = usage(pod, details)
+
+	// Blame the container the kubelet named, if any
+	container := ""
+	if len(details.Containers) > 0 {
+		container = details.Containers[0].Name
+	}
+	for _, usage := range details.Containers {
+		if usage.Usage != "" {
+			container = usage.Name
+			break
+		}
+	}
+
+	return explainer.FailureInfo{
+		PodName:       pod.Name,
+		Namespace:     pod.Namespace,
+		ContainerName: container,
+		Reason:        "Evicted",
+		Message:       pod.Status.Message,
+		Workload:      d.Workload(ctx, pod),
+		Cluster:       d.options.Cluster,
+		Eviction:      details,
+	}
+}
+
+// usage lists each container's requests and limits of the starved resource,
+// with the usage the kubelet reported for the containers it named
+func usage(pod *corev1.Pod, details *explainer.Eviction) []explainer.ContainerUsage {
+	reported := make(map[string]explainer.ContainerUsage)
+	for _, usage := range details.Containers {
+		reported[usage.Name] = usage
+	}
+
+	resource := corev1.ResourceName(details.Resource)
+	if resource != corev1.ResourceMemory && resource != corev1.ResourceEphemeralStorage {
+		return details.Containers
+	}
+
+	containers := make([]explainer.ContainerUsage, 0, len(pod.Spec.Containers))
+	for _, container := range pod.Spec.Containers {
+		usage := reported[container.Name]
+		usage.Name = container.Name
+		if request, ok := container.Resources.Requests[resource]; ok {
+			usage.Request = request.String()
+		}
+		if limit, ok := container.Resources.Limits[resource]; ok {
+			usage.Limit = limit.String()
+		}
+		containers = append(containers, usage)
+	}
+	return containers
+}
+
+// pressure lists the node's pressure conditions that are true
+func pressure(node *corev1.Node) []string {
+	if node == nil {
+		return nil
+	}
+
+	var conditions []string
+	for _, condition := range node.Status.Conditions {
+		switch condition.Type {
+		case corev1.NodeMemoryPressure, corev1.NodeDiskPressure, corev1.NodePIDPressure:
+			if condition.Status == corev1.ConditionTrue {
+				conditions = append(conditions, string(condition.Type))
+			}
+		}
+	}
+	return conditions
+}
+
+// node reads a node, or returns nil when it can't; the explanation only
+// loses the node's current pressure
+func (d *PodDetector) node(ctx context.Context, name string) *corev1.Node {
+	if name == "" {
+		return nil
+	}
+
+	node, err := d.clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		slog.Debug("Failed to get node", "node", name, "error", err)
+		return nil
+	}
+	d.observeObject(node)
+	return node
+}
+
+// forgetDeleted drops the replaced evictions whose pod is gone
+func (d *PodDetector) forgetDeleted(pods *corev1.PodList) {
+	if len(d.replaced) == 0 {
+		return
+	}
+	listed := make(map[types.UID]bool, len(pods.Items))
+	for i := range pods.Items {
+		listed[pods.Items[i].UID] = true
+	}
+	maps.DeleteFunc(d.replaced, func(uid types.UID, _ bool) bool {
+		return !listed[uid]
+	})
+}
+
+// nodeIncidentKey is the key of a node's mass eviction incident
+func nodeIncidentKey(node string) string {
+	return "node/" + node + "-Evicted"
+}
+
+// reportEvictions reports the evictions found in a poll. A node that evicted
+// massEviction pods within massEvictionWindow gets one node-level incident
+// for all of them; pods it evicts while that is open join it quietly.
+func (d *PodDetector) reportEvictions(ctx context.Context, evictions []eviction) {
+	byNode := make(map[string][]eviction)
+	for _, e := range evictions {
+		byNode[e.pod.Spec.NodeName] = append(byNode[e.pod.Spec.NodeName], e)
+	}
+
+	now := d.now()
+	for _, nodeName := range slices.Sorted(maps.Keys(byNode)) {
+		found := byNode[nodeName]
+		node := d.node(ctx, nodeName)
+		key := nodeIncidentKey(nodeName)
+		group := d.seen[key]
+
+		// Evictions of the node that were already reported one by one count
+		// towards a mass eviction, but stay reported on their own
+		var earlier []*incident
+		if group == nil {
+			for _, inc := range d.seen {
+				if inc.info.Eviction != nil && inc.info.Eviction.Node == nodeName && inc.group == "" && inc.node == "" {
+					earlier = append(earlier, inc)
+				}
+			}
+		}
+
+		newest := time.Time{}
+		for _, e := range found {
+			newest = maxTime(newest, e.at(now))
+		}
+		for _, inc := range earlier {
+			newest = maxTime(newest, inc.startedAt)
+		}
+
+		var grouped, single []eviction
+		for _, e := range found {
+			if group != nil || newest.Sub(e.at(now)) <= massEvictionWindow {
+				grouped = append(grouped, e)
+			} else {
+				single = append(single, e)
+			}
+		}
+		earlier = slices.DeleteFunc(earlier, func(inc *incident) bool {
+			return newest.Sub(inc.startedAt) > massEvictionWindow
+		})
+		slices.SortFunc(earlier, func(a, b *incident) int {
+			return a.startedAt.Compare(b.startedAt)
+		})
+		if group == nil && len(grouped)+len(earlier) < massEviction {
+			single, grouped = append(single, grouped...), nil
+		}
+
+		for _, e := range single {
+			d.report(ctx, e.pod, e.failure, d.gatherEvictionInfo(ctx, e.pod, node))
+		}
+		if len(grouped) == 0 {
+			continue
+		}
+
+		infos := make([]explainer.FailureInfo, len(grouped))
+		for i, e := range grouped {
+			infos[i] = d.gatherEvictionInfo(ctx, e.pod, node)
+		}
+
+		if group == nil {
+			group = d.reportNode(ctx, key, nodeName, grouped, infos, earlier)
+		} else {
+			// Copy, so sinks still holding the reported failure don't see it change
+			details := *group.info.Eviction
+			for _, e := range grouped {
+				details.Pods = append(slices.Clip(details.Pods), e.pod.Namespace+"/"+e.pod.Name)
+			}
+			group.info.Eviction = &details
+		}
+
+		for i, e := range grouped {
+			member := &incident{
+				info:      infos[i],
+				firstSeen: now,
+				startedAt: e.at(now),
+				podUID:    e.pod.UID,
+				group:     key,
+			}
+			d.seen[e.failure.key] = member
+			d.announceMember(e.pod, member, group)
+		}
+	}
+}
+
+// reportNode opens a node-level incident for a mass eviction
+func (d *PodDetector) reportNode(ctx context.Context, key, nodeName string, grouped []eviction, infos []explainer.FailureInfo, earlier []*incident) *incident {
+	first := infos[0]
+	details := &explainer.Eviction{
+		Node:         nodeName,
+		Resource:     first.Eviction.Resource,
+		NodePressure: first.Eviction.NodePressure,
+		Threshold:    first.Eviction.Threshold,
+		Available:    first.Eviction.Available,
+		Conditions:   first.Eviction.Conditions,
+	}
+
+	now := d.now()
+	inc := &incident{firstSeen: now, startedAt: now, node: nodeName}
+	namespaces := make(map[string]bool)
+	for _, earlier := range earlier {
+		details.Pods = append(details.Pods, earlier.info.Namespace+"/"+earlier.info.PodName)
+		namespaces[earlier.info.Namespace] = true
+		inc.startedAt = minTime(inc.startedAt, earlier.startedAt)
+	}
+	for _, e := range grouped {
+		details.Pods = append(details.Pods, e.pod.Namespace+"/"+e.pod.Name)
+		namespaces[e.pod.Namespace] = true
+		inc.startedAt = minTime(inc.startedAt, e.at(now))
+	}
+
+	// Pods of several namespaces make a cluster-wide incident
+	namespace := ""
+	if len(namespaces) == 1 {
+		namespace = slices.Collect(maps.Keys(namespaces))[0]
+	}
+
+	inc.info = explainer.FailureInfo{
+		Namespace: namespace,
+		Reason:    "Evicted",
+		Workload:  "Node/" + nodeName,
+		Cluster:   d.options.Cluster,
+		Eviction:  details,
+	}
+	d.seen[key] = inc
+	d.announceNode(ctx, inc)
+	return inc
+}
+
+// announceNode prints and notifies a mass eviction, once this replica leads
+func (d *PodDetector) announceNode(ctx context.Context, inc *incident) {
+	if !d.leading.Load() {
+		return
+	}
+	inc.reported = true
+
+	explanation := d.explain(ctx, inc.info)
+	metrics.TimeToDetect.Observe(d.now().Sub(inc.startedAt).Seconds())
+	d.notify(ctx, notifier.EventFailure, inc.info, explanation, nil)
+}
+
+// announceMember records the mass eviction on a pod it evicted, once this
+// replica leads
+func (d *PodDetector) announceMember(pod *corev1.Pod, inc, group *incident) {
+	if !d.leading.Load() {
+		return
+	}
+	inc.reported = true
+	d.recordEvent(pod, group.info)
+}
+
+// members reports whether any pod of a node-level incident is still tracked
+func (d *PodDetector) members(key string) bool {
+	for _, inc := range d.seen {
+		if inc.group == key {
+			return true
+		}
+	}
+	return false
+}
+
+// describe names what an incident is about in one-line messages
+func describe(inc *incident) string {
+	info := inc.info
+	if inc.node != "" {
+		return fmt.Sprintf("%snode %s (evicted %d pods)", clusterPrefix(info.Cluster), inc.node, len(info.Eviction.Pods))
+	}
+	return fmt.Sprintf("%s%s/%s (container %s, was %s)",
+		clusterPrefix(info.Cluster), info.Namespace, info.PodName, info.ContainerName, info.Reason)
+}
+
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,139 @@
+package detector
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const memoryEviction = "The node was low on resource: memory. Threshold quantity: 100Mi, available: 50Mi."
+
+func statefulSetPod(name, uid string, created time.Time) *corev1.Pod {
+	controller := true
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			UID:               types.UID(uid),
+			CreationTimestamp: metav1.NewTime(created),
+			OwnerReferences:   []metav1.OwnerReference{{Kind: "StatefulSet", Name: "web", Controller: &controller}},
+		},
+		Spec: corev1.PodSpec{NodeName: "node-1", Containers: []corev1.Container{{Name: "app"}}},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "app", Ready: true}},
+		},
+	}
+}
+
+func evict(pod *corev1.Pod, at time.Time) *corev1.Pod {
+	pod.Status = corev1.PodStatus{
+		Phase:   corev1.PodFailed,
+		Reason:  "Evicted",
+		Message: memoryEviction,
+		Conditions: []corev1.PodCondition{{
+			Type:               corev1.DisruptionTarget,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(at),
+		}},
+	}
+	return pod
+}
+
+func pollTimes(t *testing.T, d *PodDetector, n int) {
+	t.Helper()
+	for range n {
+		if err := d.Poll(context.Background(), "default"); err != nil {
+			t.Fatalf("poll: %v", err)
+		}
+	}
+}
+
+func TestEvictionStaysOpenUntilReplaced(t *testing.T) {
+	now := time.Now()
+	clientset := fake.NewClientset(
+		evict(statefulSetPod("web-0", "web-0-old", now.Add(-time.Hour)), now.Add(-time.Minute)),
+		statefulSetPod("web-1", "web-1", now.Add(-time.Hour)),
+	)
+	var out bytes.Buffer
+	d := New(clientset, Options{Output: &out})
+
+	// web-1 was running before the eviction, so it doesn't replace web-0
+	pollTimes(t, d, 3)
+	if got := strings.Count(out.String(), "PROBLEM DETECTED"); got != 1 {
+		t.Errorf("reported %d times, want once:\n%s", got, out.String())
+	}
+	if strings.Contains(out.String(), "RECOVERED") {
+		t.Errorf("recovered before web-0 was replaced:\n%s", out.String())
+	}
+
+	// The StatefulSet controller recreates web-0 under the same name
+	ctx := context.Background()
+	if err := clientset.CoreV1().Pods("default").Delete(ctx, "web-0", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := clientset.CoreV1().Pods("default").Create(ctx, statefulSetPod("web-0", "web-0-new", now), metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	pollTimes(t, d, 3)
+	if got := strings.Count(out.String(), "RECOVERED"); got != 1 {
+		t.Errorf("recovered %d times, want once:\n%s", got, out.String())
+	}
+	if !strings.Contains(out.String(), "replaced by pod web-0") {
+		t.Errorf("recovery doesn't name the replacement:\n%s", out.String())
+	}
+}
+
+func TestEvictedPodNotReportedAgainAfterRecovery(t *testing.T) {
+	now := time.Now()
+	clientset := fake.NewClientset(
+		evict(statefulSetPod("web-0", "web-0", now.Add(-time.Hour)), now.Add(-time.Minute)),
+	)
+	var out bytes.Buffer
+	d := New(clientset, Options{Output: &out})
+	pollTimes(t, d, 1)
+
+	// The evicted pod stays listed next to its healthy replacement
+	ctx := context.Background()
+	if _, err := clientset.CoreV1().Pods("default").Create(ctx, statefulSetPod("web-2", "web-2", now), metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	pollTimes(t, d, 3)
+
+	if got := strings.Count(out.String(), "PROBLEM DETECTED"); got != 1 {
+		t.Errorf("reported %d times, want once:\n%s", got, out.String())
+	}
+	if got := strings.Count(out.String(), "RECOVERED"); got != 1 {
+		t.Errorf("recovered %d times, want once:\n%s", got, out.String())
+	}
+}
+
+func TestMassEvictionStaysOpenUntilReplaced(t *testing.T) {
+	now := time.Now()
+	clientset := fake.NewClientset(
+		evict(statefulSetPod("web-0", "web-0", now.Add(-time.Hour)), now.Add(-3*time.Minute)),
+		evict(statefulSetPod("web-1", "web-1", now.Add(-time.Hour)), now.Add(-2*time.Minute)),
+		evict(statefulSetPod("web-2", "web-2", now.Add(-time.Hour)), now.Add(-time.Minute)),
+		statefulSetPod("web-3", "web-3", now.Add(-time.Hour)),
+	)
+	var out bytes.Buffer
+	d := New(clientset, Options{Output: &out})
+	pollTimes(t, d, 3)
+
+	if got := strings.Count(out.String(), "PROBLEM DETECTED"); got != 1 {
+		t.Errorf("reported %d times, want one node-level incident:\n%s", got, out.String())
+	}
+	if !strings.Contains(out.String(), "Node node-1 evicted 3 pods") {
+		t.Errorf("no node-level incident:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "RECOVERED") {
+		t.Errorf("recovered before the pods were replaced:\n%s", out.String())
+	}
+}
@@ -0,0 +1,272 @@
+package detector
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/explainer"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// Defaults the API server fills in for unset probe fields
+const (
+	defaultProbeTimeout   = 1
+	defaultProbePeriod    = 10
+	defaultProbeThreshold = 3
+)
+
+// unready reports whether a running container has failed its readiness probe
+// for longer than the probe allows by now, and since when it is not ready
+func unready(pod *corev1.Pod, status corev1.ContainerStatus, now time.Time) (time.Time, bool) {
+	running := status.State.Running
+	if running == nil || status.Ready || pod.DeletionTimestamp != nil {
+		return time.Time{}, false
+	}
+	// Readiness isn't checked until the startup probe passes
+	if status.Started != nil && !*status.Started {
+		return time.Time{}, false
+	}
+	container := findContainer(pod, status.Name)
+	if container == nil || container.ReadinessProbe == nil {
+		return time.Time{}, false
+	}
+
+	since := running.StartedAt.Time
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.ContainersReady && condition.Status == corev1.ConditionFalse && condition.LastTransitionTime.After(since) {
+			since = condition.LastTransitionTime.Time
+		}
+	}
+
+	// Give it the probe's budget and one more period before calling it failed
+	probe := describeProbe("readiness", container.ReadinessProbe)
+	window := time.Duration(probe.InitialDelaySeconds+(probe.FailureThreshold+1)*probe.PeriodSeconds) * time.Second
+	return since, now.Sub(since) > window
+}
+
+// blameProbe turns a crash into a probe failure when the kubelet killed the
+// container for failing its liveness or startup probe
+func (d *PodDetector) blameProbe(ctx context.Context, pod *corev1.Pod, status corev1.ContainerStatus, info *explainer.FailureInfo) {
+	if info.Reason != "CrashLoopBackOff" && info.Reason != "Error" {
+		return
+	}
+	container := findContainer(pod, status.Name)
+	if container == nil || (container.LivenessProbe == nil && container.StartupProbe == nil) {
+		return
+	}
+
+	events := d.probeEvents(ctx, pod, status.Name)
+
+	// The kubelet says which probe it restarted the container for
+	probeType := ""
+	for _, event := range slices.Backward(events) {
+		if event.Reason != "Killing" {
+			continue
+		}
+		if strings.Contains(event.Message, "failed liveness probe") {
+			probeType = "liveness"
+		} else if strings.Contains(event.Message, "failed startup probe") {
+			probeType = "startup"
+		}
+		if probeType != "" {
+			break
+		}
+	}
+	// Killing events may have expired while the failed checks are still
+	// counted; trust those only if the container looks killed rather than
+	// crashed by itself
+	if probeType == "" && killed(status) {
+		for _, event := range slices.Backward(events) {
+			if t := unhealthyType(event); t == "liveness" || t == "startup" {
+				probeType = t
+				break
+			}
+		}
+	}
+
+	spec := container.LivenessProbe
+	if probeType == "startup" {
+		spec = container.StartupProbe
+	}
+	if probeType == "" || spec == nil {
+		return
+	}
+
+	info.Reason = explainer.ProbeReason(probeType)
+	info.Probe = probeFailure(pod, status, container, probeType, spec, events)
+}
+
+// killed reports whether the last run of a container ended the way a probe
+// restart ends it: SIGKILL, or a clean exit or SIGTERM exit after the kubelet's
+// SIGTERM
+func killed(status corev1.ContainerStatus) bool {
+	last := lastTermination(status)
+	if last == nil {
+		return false
+	}
+	return last.ExitCode == 0 || last.ExitCode == 137 || last.ExitCode == 143
+}
+
+// lastTermination is how the latest run of a container ended, if it did
+func lastTermination(status corev1.ContainerStatus) *corev1.ContainerStateTerminated {
+	if terminated := status.State.Terminated; terminated != nil {
+		return terminated
+	}
+	return status.LastTerminationState.Terminated
+}
+
+// gatherReadinessInfo explains a running container that doesn't become ready
+func (d *PodDetector) gatherReadinessInfo(ctx context.Context, pod *corev1.Pod, status corev1.ContainerStatus) explainer.FailureInfo {
+	container := findContainer(pod, status.Name)
+	events := d.probeEvents(ctx, pod, status.Name)
+
+	return explainer.FailureInfo{
+		PodName:       pod.Name,
+		Namespace:     pod.Namespace,
+		ContainerName: status.Name,
+		Reason:        explainer.ProbeReason("readiness"),
+		LastLog:       d.getLastLog(ctx, pod.Namespace, pod.Name, status.Name),
+		Workload:      d.Workload(ctx, pod),
+		Cluster:       d.options.Cluster,
+		Probe:         probeFailure(pod, status, container, "readiness", container.ReadinessProbe, events),
+	}
+}
+
+// probeFailure describes a probe from its spec, the failed checks in its
+// events and how the container has been doing
+func probeFailure(pod *corev1.Pod, status corev1.ContainerStatus, container *corev1.Container,
+	probeType string, spec *corev1.Probe, events []corev1.Event) *explainer.ProbeFailure {
+
+	probe := describeProbe(probeType, spec)
+	probe.HasStartupProbe = container.StartupProbe != nil
+
+	for _, event := range slices.Backward(events) {
+		if unhealthyType(event) != probeType {
+			continue
+		}
+		if probe.Output == "" {
+			_, output, _ := strings.Cut(event.Message, ": ")
+			probe.Output = strings.TrimSpace(output)
+		}
+		probe.Failures += max(event.Count, 1)
+	}
+
+	if last := lastTermination(status); last != nil && !last.StartedAt.IsZero() {
+		probe.RanSeconds = int32(last.FinishedAt.Sub(last.StartedAt.Time).Seconds())
+	}
+
+	return probe
+}
+
+// describeProbe copies a probe's check and timing, with the API defaults for
+// unset fields
+func describeProbe(probeType string, spec *corev1.Probe) *explainer.ProbeFailure {
+	probe := &explainer.ProbeFailure{
+		Type:                probeType,
+		InitialDelaySeconds: spec.InitialDelaySeconds,
+		TimeoutSeconds:      cmp.Or(spec.TimeoutSeconds, defaultProbeTimeout),
+		PeriodSeconds:       cmp.Or(spec.PeriodSeconds, defaultProbePeriod),
+		FailureThreshold:    cmp.Or(spec.FailureThreshold, defaultProbeThreshold),
+	}
+
+	switch handler := spec.ProbeHandler; {
+	case handler.HTTPGet != nil:
+		probe.Action = "HTTP GET"
+		if handler.HTTPGet.Scheme == corev1.URISchemeHTTPS {
+			probe.Action = "HTTPS GET"
+		}
+		probe.Path = handler.HTTPGet.Path
+		probe.Port = handler.HTTPGet.Port.String()
+	case handler.TCPSocket != nil:
+		probe.Action = "TCP"
+		probe.Port = handler.TCPSocket.Port.String()
+	case handler.GRPC != nil:
+		probe.Action = "gRPC"
+		probe.Port = fmt.Sprint(handler.GRPC.Port)
+	case handler.Exec != nil:
+		probe.Action = "exec"
+		probe.Command = strings.Join(handler.Exec.Command, " ")
+	}
+	return probe
+}
+
+// probeEvents lists the kubelet's events about a container of the pod, oldest
+// first. Failing to read them only costs the probe details.
+func (d *PodDetector) probeEvents(ctx context.Context, pod *corev1.Pod, containerName string) []corev1.Event {
+	selector := fields.Set{
+		"involvedObject.kind": "Pod",
+		"involvedObject.name": pod.Name,
+	}.AsSelector().String()
+
+	list, err := d.clientset.CoreV1().Events(pod.Namespace).List(ctx, metav1.ListOptions{FieldSelector: selector})
+	if err != nil {
+		slog.Debug("Failed to list events", "namespace", pod.Namespace, "pod", pod.Name, "error", err)
+		return nil
+	}
+
+	fieldPath := "spec.containers{" + containerName + "}"
+	var events []corev1.Event
+	for _, event := range list.Items {
+		// Skip events for an earlier pod with the same name, or another container
+		if event.InvolvedObject.Name != pod.Name || (event.InvolvedObject.UID != "" && event.InvolvedObject.UID != pod.UID) {
+			continue
+		}
+		if event.InvolvedObject.FieldPath != "" && event.InvolvedObject.FieldPath != fieldPath {
+			continue
+		}
+		if event.Reason != "Unhealthy" && event.Reason != "Killing" {
+			continue
+		}
+		d.observeObject(&event)
+		events = append(events, event)
+	}
+
+	slices.SortStableFunc(events, func(a, b corev1.Event) int {
+		return EventTime(a).Compare(EventTime(b))
+	})
+	return events
+}
+
+// unhealthyType returns the probe type of an Unhealthy event, e.g. from
+// "Liveness probe failed: HTTP probe failed with statuscode: 500"
+func unhealthyType(event corev1.Event) string {
+	if event.Reason != "Unhealthy" {
+		return ""
+	}
+	for _, probeType := range []string{"liveness", "readiness", "startup"} {
+		if strings.HasPrefix(strings.ToLower(event.Message), probeType+" probe") {
+			return probeType
+		}
+	}
+	return ""
+}
+
+// EventTime is when an event last happened, whichever API version wrote it
+func EventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case event.Series != nil:
+		return event.Series.LastObservedTime.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+func findContainer(pod *corev1.Pod, name string) *corev1.Container {
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == name {
+			return &pod.Spec.Containers[i]
+		}
+	}
+	return nil
+}
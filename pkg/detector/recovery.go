@@ -0,0 +1,153 @@
+package detector
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/notifier"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// missingPolls is how many polls a pod and every pod of its workload must be
+// gone before its incident is closed as deleted, so a Recreate rollout isn't
+// mistaken for a deletion
+const missingPolls = 3
+
+// recovery decides whether an incident that is no longer failing is over: its
+// pod is healthy, a healthy pod of the same workload replaced it, or the pod
+// is gone for good. It returns nil while the incident is still open.
+func (d *PodDetector) recovery(ctx context.Context, inc *incident, pods *corev1.PodList) *notifier.Recovery {
+	info := inc.info
+	now := d.now().UTC()
+	recovery := &notifier.Recovery{
+		StartedAt:   inc.startedAt.UTC(),
+		RecoveredAt: now,
+		Restarts:    inc.restarts,
+	}
+	recovery.DurationSeconds = recovery.Duration().Seconds()
+
+	pod := findPod(pods, info.Namespace, info.PodName)
+
+	// An evicted pod never runs again: a bare one is over once deleted, one
+	// of a workload once a pod created since the eviction is healthy
+	evictedPod := info.Reason == "Evicted"
+	if pod != nil && evicted(pod) {
+		if info.Workload == "" {
+			return nil
+		}
+		pod = nil
+	}
+
+	if pod != nil {
+		inc.missing = 0
+		if pod.UID == inc.podUID {
+			if status := containerStatus(pod, info.ContainerName); status != nil {
+				inc.restarts = status.RestartCount
+				recovery.Restarts = status.RestartCount
+			}
+		}
+		if !healthy(pod) {
+			return nil
+		}
+		// StatefulSets recreate pods under the same name
+		if pod.UID != inc.podUID {
+			recovery.ReplacedBy = pod.Name
+		}
+		return recovery
+	}
+
+	// Bare pods have nothing to replace them
+	if info.Workload == "" {
+		recovery.Deleted = true
+		return recovery
+	}
+
+	replicas := false
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Namespace != info.Namespace || !d.inWorkload(ctx, pod, info.Workload) {
+			continue
+		}
+		replicas = true
+		// Replicas that were running before the eviction don't replace it
+		if evictedPod && pod.CreationTimestamp.Time.Before(inc.startedAt.Truncate(time.Second)) {
+			continue
+		}
+		if healthy(pod) {
+			inc.missing = 0
+			recovery.ReplacedBy = pod.Name
+			return recovery
+		}
+	}
+
+	if replicas {
+		inc.missing = 0
+		return nil
+	}
+	inc.missing++
+	if inc.missing < missingPolls {
+		return nil
+	}
+	recovery.Deleted = true
+	return recovery
+}
+
+// inWorkload reports whether a pod belongs to a workload ("Kind/name"). Only
+// pods whose controller could belong to it are resolved through the API.
+func (d *PodDetector) inWorkload(ctx context.Context, pod *corev1.Pod, workload string) bool {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return false
+	}
+	if owner.Kind+"/"+owner.Name == workload {
+		return true
+	}
+
+	// ReplicaSets and Jobs are named after their Deployment or CronJob
+	_, name, _ := strings.Cut(workload, "/")
+	if (owner.Kind != "ReplicaSet" && owner.Kind != "Job") || !strings.HasPrefix(owner.Name, name+"-") {
+		return false
+	}
+	return d.Workload(ctx, pod) == workload
+}
+
+// healthy reports whether a pod is running with every container ready, or
+// has completed successfully
+func healthy(pod *corev1.Pod) bool {
+	if pod.DeletionTimestamp != nil {
+		return false
+	}
+	if pod.Status.Phase == corev1.PodSucceeded {
+		return true
+	}
+	if pod.Status.Phase != corev1.PodRunning || len(pod.Status.ContainerStatuses) == 0 {
+		return false
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if !status.Ready {
+			return false
+		}
+	}
+	return true
+}
+
+func findPod(pods *corev1.PodList, namespace, name string) *corev1.Pod {
+	for i := range pods.Items {
+		if pods.Items[i].Namespace == namespace && pods.Items[i].Name == name {
+			return &pods.Items[i]
+		}
+	}
+	return nil
+}
+
+func containerStatus(pod *corev1.Pod, name string) *corev1.ContainerStatus {
+	for i := range pod.Status.ContainerStatuses {
+		if pod.Status.ContainerStatuses[i].Name == name {
+			return &pod.Status.ContainerStatuses[i]
+		}
+	}
+	return nil
+}
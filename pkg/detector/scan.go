@@ -0,0 +1,71 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/explainer"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Scan lists the pods once and returns every current failure, without
+// printing, recording or notifying anything
+func (d *PodDetector) Scan(ctx context.Context, namespace string) ([]explainer.FailureInfo, error) {
+	pods, err := d.listPods(ctx, namespace, d.listOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	var found []explainer.FailureInfo
+	for i := range pods.Items {
+		found = append(found, d.Failures(ctx, &pods.Items[i])...)
+	}
+	return found, nil
+}
+
+// Failures returns the current failures of a single pod
+func (d *PodDetector) Failures(ctx context.Context, pod *corev1.Pod) []explainer.FailureInfo {
+	var found []explainer.FailureInfo
+	for _, f := range d.failures(pod) {
+		found = append(found, d.gather(ctx, pod, f))
+	}
+	return found
+}
+
+// listOptions applies the pod name or label selector from Options
+func (d *PodDetector) listOptions() metav1.ListOptions {
+	listOptions := metav1.ListOptions{}
+
+	if d.options.LabelSelector != "" {
+		listOptions.LabelSelector = d.options.LabelSelector
+	}
+
+	if d.options.PodName != "" {
+		listOptions.FieldSelector = fmt.Sprintf("metadata.name=%s", d.options.PodName)
+	}
+
+	return listOptions
+}
+
+// Settled reports whether every pod has finished starting: succeeded, failed
+// for good (e.g. evicted, or a failed Job pod), or running with all
+// containers ready
+func (d *PodDetector) Settled(ctx context.Context, namespace string) (bool, error) {
+	pods, err := d.listPods(ctx, namespace, d.listOptions())
+	if err != nil {
+		return false, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	for i := range pods.Items {
+		// Failed pods won't change; they are failures for the scan to report
+		if pods.Items[i].Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if !healthy(&pods.Items[i]) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
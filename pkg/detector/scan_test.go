@@ -0,0 +1,48 @@
+package detector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSettledWithFailedPods(t *testing.T) {
+	now := time.Now()
+	starting := statefulSetPod("web-2", "web-2", now)
+	starting.Status.ContainerStatuses[0].Ready = false
+
+	tests := []struct {
+		name string
+		pods []*corev1.Pod
+		want bool
+	}{
+		{"ready", []*corev1.Pod{statefulSetPod("web-0", "web-0", now)}, true},
+		{"evicted", []*corev1.Pod{
+			statefulSetPod("web-0", "web-0", now),
+			evict(statefulSetPod("web-1", "web-1", now), now),
+		}, true},
+		{"still starting", []*corev1.Pod{evict(statefulSetPod("web-1", "web-1", now), now), starting}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewClientset()
+			for _, pod := range tt.pods {
+				if err := clientset.Tracker().Add(pod); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			settled, err := New(clientset, Options{}).Settled(context.Background(), "default")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if settled != tt.want {
+				t.Errorf("Settled() = %v, want %v", settled, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package detector
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Workload returns the top-level controller of a pod as "Kind/name",
+// following ReplicaSets to their Deployment and Jobs to their CronJob.
+// Bare pods have no workload and return "".
+func (d *PodDetector) Workload(ctx context.Context, pod *corev1.Pod) string {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return ""
+	}
+
+	switch owner.Kind {
+	case "ReplicaSet":
+		rs, err := d.clientset.AppsV1().ReplicaSets(pod.Namespace).Get(ctx, owner.Name, metav1.GetOptions{})
+		if err == nil {
+			d.observeObject(rs)
+			if parent := metav1.GetControllerOf(rs); parent != nil {
+				return parent.Kind + "/" + parent.Name
+			}
+		}
+	case "Job":
+		job, err := d.clientset.BatchV1().Jobs(pod.Namespace).Get(ctx, owner.Name, metav1.GetOptions{})
+		if err == nil {
+			d.observeObject(job)
+			if parent := metav1.GetControllerOf(job); parent != nil {
+				return parent.Kind + "/" + parent.Name
+			}
+		}
+	}
+
+	return owner.Kind + "/" + owner.Name
+}
+
+func (d *PodDetector) observeObject(object runtime.Object) {
+	if d.options.Observer != nil {
+		d.options.Observer.ObserveObject(object)
+	}
+}
@@ -0,0 +1,265 @@
+package explainer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+)
+
+// Eviction describes why the kubelet evicted a pod, or many pods of one node
+type Eviction struct {
+	Node string `json:"node,omitempty"`
+
+	// Resource is what ran out, e.g. "memory" or "ephemeral-storage"
+	Resource string `json:"resource,omitempty"`
+
+	// NodePressure tells whether the node ran low on the resource, rather
+	// than the pod exceeding its own ephemeral storage limit
+	NodePressure bool   `json:"nodePressure,omitempty"`
+	Threshold    string `json:"threshold,omitempty"` // the kubelet's eviction threshold
+	Available    string `json:"available,omitempty"` // what the node had left at the time
+
+	// Conditions lists the node's pressure conditions that are still true,
+	// e.g. "MemoryPressure"
+	Conditions []string `json:"conditions,omitempty"`
+
+	QOSClass   string           `json:"qosClass,omitempty"`
+	Containers []ContainerUsage `json:"containers,omitempty"`
+
+	// Pods lists the evicted pods as namespace/name, for node-level incidents
+	Pods []string `json:"pods,omitempty"`
+}
+
+// ContainerUsage compares what a container used of the starved resource with
+// what it requested
+type ContainerUsage struct {
+	Name    string `json:"name"`
+	Usage   string `json:"usage,omitempty"` // empty unless the kubelet named the container
+	Request string `json:"request,omitempty"`
+	Limit   string `json:"limit,omitempty"`
+}
+
+var (
+	lowOnResource = regexp.MustCompile(`The node was low on resource: ([\w.-]+)\.`)
+	threshold     = regexp.MustCompile(`Threshold quantity: ([^,]+), available: (\S+?)\.(?:\s|$)`)
+	containerUse  = regexp.MustCompile(`Container ([\w.-]+) was using ([^,]+), request is ([^,]+), has larger consumption of`)
+)
+
+// ParseEviction reads the kubelet's eviction message, e.g. "The node was low
+// on resource: memory. Threshold quantity: 100Mi, available: 50Mi. Container
+// app was using 1Gi, request is 256Mi, has larger consumption of memory."
+func ParseEviction(message string) *Eviction {
+	eviction := &Eviction{}
+	if match := lowOnResource.FindStringSubmatch(message); match != nil {
+		eviction.Resource = match[1]
+		eviction.NodePressure = true
+	} else if strings.Contains(message, "ephemeral local storage") || strings.Contains(message, "EmptyDir volume") {
+		eviction.Resource = "ephemeral-storage"
+	}
+
+	if match := threshold.FindStringSubmatch(message); match != nil {
+		eviction.Threshold, eviction.Available = match[1], match[2]
+	}
+	for _, match := range containerUse.FindAllStringSubmatch(message, -1) {
+		eviction.Containers = append(eviction.Containers, ContainerUsage{Name: match[1], Usage: match[2], Request: match[3]})
+	}
+	return eviction
+}
+
+// evictedPods returns the count of evicted pods for node-level incidents, 0
+// for a single pod
+func (e *Eviction) evictedPods() int {
+	if e == nil {
+		return 0
+	}
+	return len(e.Pods)
+}
+
+// nodeLevel reports whether a failure stands for several evicted pods of a node
+func nodeLevel(info FailureInfo) bool {
+	return info.Reason == "Evicted" && info.Eviction.evictedPods() > 0
+}
+
+func summarizeEviction(info FailureInfo) string {
+	eviction := info.Eviction
+	if eviction == nil {
+		eviction = &Eviction{}
+	}
+
+	cause := ""
+	switch {
+	case eviction.NodePressure:
+		cause = " because the node ran low on " + eviction.Resource
+	case eviction.Resource != "":
+		cause = " for using more " + eviction.Resource + " than its limit"
+	}
+
+	var summary string
+	if nodeLevel(info) {
+		summary = fmt.Sprintf("Evicted: node %s evicted %d pods%s", eviction.Node, len(eviction.Pods), cause)
+	} else {
+		summary = fmt.Sprintf("Evicted: pod %s/%s was evicted", info.Namespace, info.PodName)
+		if eviction.Node != "" {
+			summary += " from node " + eviction.Node
+		}
+		summary += cause
+	}
+	if info.Cluster != "" {
+		summary = fmt.Sprintf("[%s] %s", info.Cluster, summary)
+	}
+	return summary
+}
+
+// qosNotes explains where each QoS class stands when the kubelet picks pods to evict
+var qosNotes = map[string]string{
+	"BestEffort": "No requests at all, so it is among the first pods evicted under node pressure.",
+	"Burstable":  "Pods using more than they request are evicted before pods within their requests.",
+	"Guaranteed": "Requests equal limits, so it is evicted only after BestEffort and Burstable pods.",
+}
+
+func explainEviction(info FailureInfo) string {
+	eviction := info.Eviction
+	if eviction == nil {
+		eviction = &Eviction{}
+	}
+
+	explanation := "❌ WHAT HAPPENED:\n"
+	if nodeLevel(info) {
+		explanation += fmt.Sprintf("Node %s evicted %d pods.\n\n", eviction.Node, len(eviction.Pods))
+	} else {
+		explanation += "The kubelet evicted your pod and stopped all its containers.\n\n"
+	}
+
+	explanation += "🤔 WHAT THIS MEANS:\n"
+	if eviction.NodePressure {
+		explanation += fmt.Sprintf("The node ran low on %s, so the kubelet evicted pods to reclaim it.\n", eviction.Resource)
+	} else if eviction.Resource != "" {
+		explanation += fmt.Sprintf("The pod used more %s than its limits allow.\n", eviction.Resource)
+	}
+	explanation += "Evicted pods are not restarted: controllers create replacements,\n"
+	explanation += "bare pods are gone for good.\n\n"
+
+	if eviction.Node != "" {
+		explanation += "🖥️  NODE:\n"
+		explanation += "Node: " + eviction.Node + "\n"
+		if eviction.Threshold != "" {
+			explanation += fmt.Sprintf("%s available: %s (eviction threshold %s)\n", eviction.Resource, eviction.Available, eviction.Threshold)
+		}
+		if len(eviction.Conditions) > 0 {
+			explanation += "Pressure now: " + strings.Join(eviction.Conditions, ", ") + "\n"
+		} else if eviction.NodePressure {
+			explanation += "Pressure now: none, the node has recovered for the moment\n"
+		}
+		explanation += "\n"
+	}
+
+	if nodeLevel(info) {
+		explanation += "📦 EVICTED PODS:\n"
+		for i, pod := range eviction.Pods {
+			if i == 10 {
+				explanation += fmt.Sprintf("… and %d more\n", len(eviction.Pods)-i)
+				break
+			}
+			explanation += "- " + pod + "\n"
+		}
+		explanation += "\n"
+	} else if eviction.QOSClass != "" || len(eviction.Containers) > 0 {
+		explanation += "📦 POD:\n"
+		if eviction.QOSClass != "" {
+			explanation += fmt.Sprintf("QoS class: %s. %s\n", eviction.QOSClass, qosNotes[eviction.QOSClass])
+		}
+		if len(eviction.Containers) > 0 {
+			explanation += formatUsage(eviction)
+		}
+		explanation += "\n"
+	}
+
+	if info.Message != "" {
+		explanation += "📝 EVICTION MESSAGE:\n" + info.Message + "\n\n"
+	}
+
+	explanation += formatFixes(FixSteps(info))
+
+	explanation += formatCommands(DebugCommands(info))
+
+	return explanation
+}
+
+// formatUsage renders the usage versus requests of each container
+func formatUsage(eviction *Eviction) string {
+	var b strings.Builder
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "CONTAINER\t%s USED\tREQUEST\tLIMIT\n", strings.ToUpper(eviction.Resource))
+	for _, container := range eviction.Containers {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", container.Name, valueOr(container.Usage, "?"), valueOr(container.Request, "none"), valueOr(container.Limit, "none"))
+	}
+	tw.Flush()
+	return b.String()
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" || value == "0" {
+		return fallback
+	}
+	return value
+}
+
+// evictionFixes suggests fixes by starved resource
+func evictionFixes(info FailureInfo) []string {
+	resource := ""
+	if info.Eviction != nil {
+		resource = info.Eviction.Resource
+	}
+
+	requests := "requests"
+	if resource != "" {
+		requests = resource + " requests"
+	}
+	fixes := []string{"Set " + requests + " that match real usage: pods using more than they request are evicted first"}
+	switch resource {
+	case "memory":
+		fixes = append(fixes, "Fix memory growth in the application, or raise its memory request and limit")
+	case "ephemeral-storage":
+		fixes = append(fixes,
+			"Write less to the container filesystem and logs, or use a volume for large data",
+			"Set an ephemeral-storage request and limit so the scheduler accounts for it")
+	case "pids":
+		fixes = append(fixes, "Find the process leak, or set a pod PID limit")
+	}
+	if resource == "" {
+		fixes = append(fixes, "Check what else uses the node's resources, or add capacity to the node pool")
+	} else {
+		fixes = append(fixes, "Check what else uses the node's "+resource+", or add capacity to the node pool")
+	}
+	if nodeLevel(info) {
+		fixes = append(fixes, "Cordon the node if the pressure persists, so no new pods land on it")
+	}
+	return fixes
+}
+
+func evictionCommands(info FailureInfo) []DebugCommand {
+	ns := info.Namespace
+	node := "<node>"
+	if info.Eviction != nil && info.Eviction.Node != "" {
+		node = info.Eviction.Node
+	}
+
+	var commands []DebugCommand
+	if !nodeLevel(info) {
+		commands = append(commands,
+			DebugCommand{"Read the eviction message", fmt.Sprintf("kubectl get pod %s -n %s -o jsonpath='{.status.message}'", info.PodName, ns)},
+			DebugCommand{"Check the pod's QoS class and requests", fmt.Sprintf("kubectl get pod %s -n %s -o jsonpath='{.status.qosClass} {.spec.containers[*].resources}'", info.PodName, ns)},
+		)
+	}
+	commands = append(commands,
+		DebugCommand{"Check the node's pressure conditions", fmt.Sprintf("kubectl describe node %s", node)},
+		DebugCommand{"View the node's current usage (if metrics-server is installed)", fmt.Sprintf("kubectl top node %s", node)},
+		DebugCommand{"List the pods evicted from the node", fmt.Sprintf("kubectl get pods -A --field-selector spec.nodeName=%s,status.phase=Failed", node)},
+		DebugCommand{"View recent eviction events", "kubectl get events -A --field-selector reason=Evicted --sort-by='.lastTimestamp'"},
+	)
+	if ns != "" {
+		commands = append(commands, DebugCommand{"Delete the evicted pods once investigated", fmt.Sprintf("kubectl delete pods -n %s --field-selector status.phase=Failed", ns)})
+	}
+	return commands
+}
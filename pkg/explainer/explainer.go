@@ -7,13 +7,244 @@ import (
 
 // FailureInfo contains details about a pod failure
 type FailureInfo struct {
-	PodName       string
-	Namespace     string
-	ContainerName string
-	Reason        string
-	Message       string
-	ExitCode      int32
-	LastLog       string
+	PodName       string `json:"podName"`
+	Namespace     string `json:"namespace"`
+	ContainerName string `json:"containerName"`
+	Reason        string `json:"reason"`
+	Message       string `json:"message,omitempty"`
+	ExitCode      int32  `json:"exitCode"`
+	LastLog       string `json:"lastLog,omitempty"`
+	Workload      string `json:"workload,omitempty"` // e.g. "Deployment/web"; empty for bare pods
+	Cluster       string `json:"cluster,omitempty"`  // set when watching several clusters
+
+	// Probe is the failing probe, for probe failures
+	Probe *ProbeFailure `json:"probe,omitempty"`
+
+	// Eviction says why the pod, or the pods of a node, were evicted
+	Eviction *Eviction `json:"eviction,omitempty"`
+}
+
+// DebugCommand is a single suggested command with a short description
+type DebugCommand struct {
+	Description string `json:"description,omitempty"`
+	Command     string `json:"command"`
+}
+
+// Diagnosis is the structured form of an explanation, for outputs that need
+// the individual pieces rather than the rendered text
+type Diagnosis struct {
+	Summary  string         `json:"summary"`
+	Evidence []string       `json:"evidence,omitempty"`
+	Fixes    []string       `json:"fixes,omitempty"`
+	Commands []DebugCommand `json:"commands,omitempty"`
+}
+
+// Diagnose returns the summary, evidence, fix steps and debug commands for a failure
+func Diagnose(info FailureInfo) Diagnosis {
+	return Diagnosis{
+		Summary:  Summary(info),
+		Evidence: Evidence(info),
+		Fixes:    FixSteps(info),
+		Commands: DebugCommands(info),
+	}
+}
+
+// Summary returns a one-line description of the failure
+func Summary(info FailureInfo) string {
+	if info.Reason == "Evicted" {
+		return summarizeEviction(info)
+	}
+
+	var what string
+	switch info.Reason {
+	case "CrashLoopBackOff":
+		what = "keeps crashing and restarting"
+	case "ImagePullBackOff", "ErrImagePull":
+		what = "cannot pull its image"
+	case "OOMKilled":
+		what = "was killed for running out of memory"
+	case "CreateContainerConfigError":
+		what = "has an invalid configuration"
+	case "RunContainerError":
+		what = "could not be started"
+	case "InvalidImageName":
+		what = "has an invalid image name"
+	case "LivenessProbeFailed":
+		what = "is killed by its failing liveness probe"
+	case "StartupProbeFailed":
+		what = "is killed for not passing its startup probe"
+	case "ReadinessProbeFailed":
+		what = "never becomes ready"
+	default:
+		what = "failed"
+	}
+
+	summary := fmt.Sprintf("%s: container %s in pod %s/%s %s",
+		info.Reason, info.ContainerName, info.Namespace, info.PodName, what)
+	if info.Cluster != "" {
+		summary = fmt.Sprintf("[%s] %s", info.Cluster, summary)
+	}
+	if info.ExitCode != 0 {
+		summary += fmt.Sprintf(" (exit code %d)", info.ExitCode)
+	}
+	return summary
+}
+
+// Evidence lists the observations a diagnosis is based on
+func Evidence(info FailureInfo) []string {
+	var evidence []string
+
+	if info.Message != "" {
+		evidence = append(evidence, "Message: "+info.Message)
+	}
+	if info.ExitCode != 0 {
+		evidence = append(evidence, strings.TrimPrefix(ExplainExitCode(info.ExitCode), "→ "))
+	}
+	if probe := info.Probe; probe != nil {
+		evidence = append(evidence, fmt.Sprintf("%s probe: %s (initialDelaySeconds %d, timeoutSeconds %d, periodSeconds %d, failureThreshold %d)",
+			probe.Type, probe.Target(), probe.InitialDelaySeconds, probe.TimeoutSeconds, probe.PeriodSeconds, probe.FailureThreshold))
+		if probe.Output != "" {
+			evidence = append(evidence, "Probe output: "+probe.Output)
+		}
+		for _, warning := range probe.Warnings() {
+			evidence = append(evidence, "Likely misconfiguration: "+warning)
+		}
+	}
+	if info.LastLog != "" {
+		evidence = append(evidence, "Last log lines:\n"+strings.TrimRight(info.LastLog, "\n"))
+	}
+
+	return evidence
+}
+
+// FixSteps returns the suggested fixes for a failure, most important first
+func FixSteps(info FailureInfo) []string {
+	switch info.Reason {
+	case "CrashLoopBackOff":
+		return []string{
+			"Check application logs for startup errors",
+			"Verify environment variables and configuration",
+			"Test the container image locally",
+			"Check dependencies (database, APIs, etc.)",
+		}
+	case "ImagePullBackOff", "ErrImagePull":
+		return []string{
+			"Verify the image name and tag are correct",
+			"Check if the image exists in the registry",
+			"Ensure image pull secrets are configured correctly",
+			"Verify registry credentials are valid",
+		}
+	case "OOMKilled":
+		return []string{
+			"Increase memory limits in your deployment",
+			"Fix memory leaks in your application",
+			"Optimize memory usage",
+			"Use memory profiling tools",
+		}
+	case "CreateContainerConfigError":
+		return []string{
+			"Verify all ConfigMaps and Secrets exist",
+			"Check volume mount paths are correct",
+			"Ensure environment variables reference valid resources",
+			"Validate YAML syntax",
+		}
+	case "InvalidImageName":
+		return []string{"Correct the image reference in the pod spec"}
+	case "Evicted":
+		return evictionFixes(info)
+	case "StartupProbeFailed":
+		return []string{
+			"Check the probe output: is it the wrong port or path, or is the app still starting?",
+			"Raise failureThreshold so failureThreshold × periodSeconds covers the slowest start",
+			"Check application logs for what the startup waits on",
+		}
+	case "LivenessProbeFailed":
+		return []string{
+			"Check the probe output: is it the wrong port or path, a timeout, or an error from the app?",
+			"Give slow starts a startupProbe rather than a long initialDelaySeconds",
+			"Raise timeoutSeconds or failureThreshold if the endpoint is only slow",
+			"Keep liveness checks free of dependencies like databases",
+		}
+	case "ReadinessProbeFailed":
+		return []string{
+			"Check the probe output to see why the endpoint reports not ready",
+			"Verify the dependencies the endpoint checks are reachable",
+			"Check the probe port and path match what the app serves",
+			"Raise timeoutSeconds if the endpoint is only slow",
+		}
+	default:
+		return []string{"Check the pod description and events for details"}
+	}
+}
+
+// DebugCommands returns the kubectl commands that help investigate a failure
+func DebugCommands(info FailureInfo) []DebugCommand {
+	pod, ns := info.PodName, info.Namespace
+
+	switch info.Reason {
+	case "CrashLoopBackOff":
+		return []DebugCommand{
+			{"View recent logs (last 50 lines)", fmt.Sprintf("kubectl logs %s -n %s --tail=50", pod, ns)},
+			{"View logs from previous crash", fmt.Sprintf("kubectl logs %s -n %s --previous", pod, ns)},
+			{"View all logs with timestamps", fmt.Sprintf("kubectl logs %s -n %s --timestamps=true --all-containers=true", pod, ns)},
+			{"Stream logs in real-time", fmt.Sprintf("kubectl logs %s -n %s -f", pod, ns)},
+			{"Get detailed pod information", fmt.Sprintf("kubectl describe pod %s -n %s", pod, ns)},
+			{"Check pod events (last activities)", fmt.Sprintf("kubectl get events -n %s --field-selector involvedObject.name=%s --sort-by='.lastTimestamp'", ns, pod)},
+			{"Get pod YAML configuration", fmt.Sprintf("kubectl get pod %s -n %s -o yaml", pod, ns)},
+			{"Check environment variables", fmt.Sprintf("kubectl exec %s -n %s -- env", pod, ns)},
+			{"Try to exec into container (if it stays up long enough)", fmt.Sprintf("kubectl exec -it %s -n %s -- /bin/sh", pod, ns)},
+			{"Check resource usage", fmt.Sprintf("kubectl top pod %s -n %s", pod, ns)},
+		}
+	case "ImagePullBackOff", "ErrImagePull":
+		return []DebugCommand{
+			{"Check pod description for image details", fmt.Sprintf("kubectl describe pod %s -n %s | grep -A5 'Image'", pod, ns)},
+			{"View detailed error message", fmt.Sprintf("kubectl describe pod %s -n %s | grep -A10 'Events'", pod, ns)},
+			{"Get pod events", fmt.Sprintf("kubectl get events -n %s --field-selector involvedObject.name=%s", ns, pod)},
+			{"Check if image pull secret exists", fmt.Sprintf("kubectl get secrets -n %s", ns)},
+			{"Describe the image pull secret", fmt.Sprintf("kubectl get secret  -n %s -o yaml", ns)},
+			{"Get the image name, then try 'docker pull' with it locally", fmt.Sprintf("kubectl get pod %s -n %s -o jsonpath='{.spec.containers[*].image}'", pod, ns)},
+			{"Check deployment/pod spec", fmt.Sprintf("kubectl get pod %s -n %s -o yaml | grep -A5 'image:'", pod, ns)},
+			{"List all image pull secrets in namespace", fmt.Sprintf("kubectl get serviceaccount default -n %s -o yaml | grep -A3 'imagePullSecrets'", ns)},
+		}
+	case "OOMKilled":
+		return []DebugCommand{
+			{"Check current memory limits", fmt.Sprintf("kubectl get pod %s -n %s -o jsonpath='{.spec.containers[*].resources}'", pod, ns)},
+			{"View actual memory usage (if metrics-server is installed)", fmt.Sprintf("kubectl top pod %s -n %s", pod, ns)},
+			{"Check historical resource usage", fmt.Sprintf("kubectl describe pod %s -n %s | grep -A5 'Limits\\|Requests'", pod, ns)},
+			{"View OOM events", fmt.Sprintf("kubectl get events -n %s --field-selector reason=OOMKilling", ns)},
+			{"Check node memory pressure", "kubectl describe nodes | grep -A5 'Memory'"},
+			{"Get pod restart count", fmt.Sprintf("kubectl get pod %s -n %s -o jsonpath='{.status.containerStatuses[*].restartCount}'", pod, ns)},
+			{"View logs before OOM kill", fmt.Sprintf("kubectl logs %s -n %s --previous --tail=100", pod, ns)},
+		}
+	case "CreateContainerConfigError":
+		return []DebugCommand{
+			{"Get detailed error description", fmt.Sprintf("kubectl describe pod %s -n %s", pod, ns)},
+			{"Check if referenced ConfigMaps exist", fmt.Sprintf("kubectl get configmaps -n %s", ns)},
+			{"Check if referenced Secrets exist", fmt.Sprintf("kubectl get secrets -n %s", ns)},
+			{"View pod YAML to find configuration issues", fmt.Sprintf("kubectl get pod %s -n %s -o yaml", pod, ns)},
+			{"Check volume mounts", fmt.Sprintf("kubectl get pod %s -n %s -o jsonpath='{.spec.volumes}'", pod, ns)},
+		}
+	case "RunContainerError":
+		return []DebugCommand{
+			{"", fmt.Sprintf("kubectl describe pod %s -n %s", pod, ns)},
+			{"", fmt.Sprintf("kubectl get events -n %s --field-selector involvedObject.name=%s", ns, pod)},
+		}
+	case "InvalidImageName":
+		return []DebugCommand{
+			{"Check the image name", fmt.Sprintf("kubectl get pod %s -n %s -o jsonpath='{.spec.containers[*].image}'", pod, ns)},
+		}
+	case "LivenessProbeFailed", "StartupProbeFailed", "ReadinessProbeFailed":
+		return probeCommands(info)
+	case "Evicted":
+		return evictionCommands(info)
+	default:
+		return []DebugCommand{
+			{"Get detailed pod information", fmt.Sprintf("kubectl describe pod %s -n %s", pod, ns)},
+			{"View logs", fmt.Sprintf("kubectl logs %s -n %s", pod, ns)},
+			{"View previous logs (if restarted)", fmt.Sprintf("kubectl logs %s -n %s --previous", pod, ns)},
+			{"Check events", fmt.Sprintf("kubectl get events -n %s --field-selector involvedObject.name=%s --sort-by='.lastTimestamp'", ns, pod)},
+		}
+	}
 }
 
 // Explain generates a human-friendly explanation with debug commands
@@ -22,8 +253,15 @@ func Explain(info FailureInfo) string {
 
 	explanation.WriteString(fmt.Sprintf("🚨 PROBLEM DETECTED\n"))
 	explanation.WriteString(fmt.Sprintf("=====================================\n"))
-	explanation.WriteString(fmt.Sprintf("Pod: %s/%s\n", info.Namespace, info.PodName))
-	explanation.WriteString(fmt.Sprintf("Container: %s\n\n", info.ContainerName))
+	if info.Cluster != "" {
+		explanation.WriteString(fmt.Sprintf("Cluster: %s\n", info.Cluster))
+	}
+	if nodeLevel(info) {
+		explanation.WriteString(fmt.Sprintf("Node: %s\n\n", info.Eviction.Node))
+	} else {
+		explanation.WriteString(fmt.Sprintf("Pod: %s/%s\n", info.Namespace, info.PodName))
+		explanation.WriteString(fmt.Sprintf("Container: %s\n\n", info.ContainerName))
+	}
 
 	// Analyze based on reason
 	switch info.Reason {
@@ -39,6 +277,10 @@ func Explain(info FailureInfo) string {
 		explanation.WriteString(explainRunContainerError(info))
 	case "InvalidImageName":
 		explanation.WriteString(explainInvalidImageName(info))
+	case "LivenessProbeFailed", "StartupProbeFailed", "ReadinessProbeFailed":
+		explanation.WriteString(explainProbeFailure(info))
+	case "Evicted":
+		explanation.WriteString(explainEviction(info))
 	default:
 		explanation.WriteString(explainGeneric(info))
 	}
@@ -57,7 +299,7 @@ func explainCrashLoopBackOff(info FailureInfo) string {
 	// Analyze exit code
 	if info.ExitCode != 0 {
 		explanation += fmt.Sprintf("Exit Code: %d\n", info.ExitCode)
-		explanation += explainExitCode(info.ExitCode) + "\n\n"
+		explanation += ExplainExitCode(info.ExitCode) + "\n\n"
 	}
 
 	// Show last error if available
@@ -66,44 +308,9 @@ func explainCrashLoopBackOff(info FailureInfo) string {
 		explanation += info.LastLog + "\n\n"
 	}
 
-	explanation += "🔧 HOW TO FIX:\n"
-	explanation += "1. Check application logs for startup errors\n"
-	explanation += "2. Verify environment variables and configuration\n"
-	explanation += "3. Test the container image locally\n"
-	explanation += "4. Check dependencies (database, APIs, etc.)\n\n"
-
-	explanation += "🐛 DEBUG COMMANDS:\n"
-	explanation += "-------------------\n\n"
-
-	explanation += "# 1. View recent logs (last 50 lines)\n"
-	explanation += fmt.Sprintf("kubectl logs %s -n %s --tail=50\n\n", info.PodName, info.Namespace)
-
-	explanation += "# 2. View logs from previous crash\n"
-	explanation += fmt.Sprintf("kubectl logs %s -n %s --previous\n\n", info.PodName, info.Namespace)
-
-	explanation += "# 3. View all logs with timestamps\n"
-	explanation += fmt.Sprintf("kubectl logs %s -n %s --timestamps=true --all-containers=true\n\n", info.PodName, info.Namespace)
-
-	explanation += "# 4. Stream logs in real-time\n"
-	explanation += fmt.Sprintf("kubectl logs %s -n %s -f\n\n", info.PodName, info.Namespace)
+	explanation += formatFixes(FixSteps(info))
 
-	explanation += "# 5. Get detailed pod information\n"
-	explanation += fmt.Sprintf("kubectl describe pod %s -n %s\n\n", info.PodName, info.Namespace)
-
-	explanation += "# 6. Check pod events (last activities)\n"
-	explanation += fmt.Sprintf("kubectl get events -n %s --field-selector involvedObject.name=%s --sort-by='.lastTimestamp'\n\n", info.Namespace, info.PodName)
-
-	explanation += "# 7. Get pod YAML configuration\n"
-	explanation += fmt.Sprintf("kubectl get pod %s -n %s -o yaml\n\n", info.PodName, info.Namespace)
-
-	explanation += "# 8. Check environment variables\n"
-	explanation += fmt.Sprintf("kubectl exec %s -n %s -- env\n\n", info.PodName, info.Namespace)
-
-	explanation += "# 9. Try to exec into container (if it stays up long enough)\n"
-	explanation += fmt.Sprintf("kubectl exec -it %s -n %s -- /bin/sh\n\n", info.PodName, info.Namespace)
-
-	explanation += "# 10. Check resource usage\n"
-	explanation += fmt.Sprintf("kubectl top pod %s -n %s\n\n", info.PodName, info.Namespace)
+	explanation += formatCommands(DebugCommands(info))
 
 	explanation += "📊 COMMON CAUSES:\n"
 	explanation += "- Missing required environment variables\n"
@@ -125,41 +332,9 @@ func explainImagePullError(info FailureInfo) string {
 	explanation += "The image specified in your deployment doesn't exist, has the wrong name,\n"
 	explanation += "or Kubernetes doesn't have permission to pull it from the registry.\n\n"
 
-	explanation += "🔧 HOW TO FIX:\n"
-	explanation += "1. Verify the image name and tag are correct\n"
-	explanation += "2. Check if the image exists in the registry\n"
-	explanation += "3. Ensure image pull secrets are configured correctly\n"
-	explanation += "4. Verify registry credentials are valid\n\n"
-
-	explanation += "🐛 DEBUG COMMANDS:\n"
-	explanation += "-------------------\n\n"
-
-	explanation += "# 1. Check pod description for image details\n"
-	explanation += fmt.Sprintf("kubectl describe pod %s -n %s | grep -A5 'Image'\n\n", info.PodName, info.Namespace)
-
-	explanation += "# 2. View detailed error message\n"
-	explanation += fmt.Sprintf("kubectl describe pod %s -n %s | grep -A10 'Events'\n\n", info.PodName, info.Namespace)
-
-	explanation += "# 3. Get pod events\n"
-	explanation += fmt.Sprintf("kubectl get events -n %s --field-selector involvedObject.name=%s\n\n", info.Namespace, info.PodName)
-
-	explanation += "# 4. Check if image pull secret exists\n"
-	explanation += fmt.Sprintf("kubectl get secrets -n %s\n\n", info.Namespace)
-
-	explanation += "# 5. Describe the image pull secret\n"
-	explanation += fmt.Sprintf("kubectl get secret  -n %s -o yaml\n\n", info.Namespace)
-
-	explanation += "# 6. Test pulling the image locally (if using Docker)\n"
-	explanation += fmt.Sprintf("# First, get the image name from pod:\n")
-	explanation += fmt.Sprintf("kubectl get pod %s -n %s -o jsonpath='{.spec.containers[*].image}'\n", info.PodName, info.Namespace)
-	explanation += "# Then try pulling it:\n"
-	explanation += "docker pull \n\n"
-
-	explanation += "# 7. Check deployment/pod spec\n"
-	explanation += fmt.Sprintf("kubectl get pod %s -n %s -o yaml | grep -A5 'image:'\n\n", info.PodName, info.Namespace)
+	explanation += formatFixes(FixSteps(info))
 
-	explanation += "# 8. List all image pull secrets in namespace\n"
-	explanation += fmt.Sprintf("kubectl get serviceaccount default -n %s -o yaml | grep -A3 'imagePullSecrets'\n\n", info.Namespace)
+	explanation += formatCommands(DebugCommands(info))
 
 	explanation += "📊 COMMON CAUSES:\n"
 	explanation += "- Typo in image name or tag\n"
@@ -189,35 +364,9 @@ func explainOOMKilled(info FailureInfo) string {
 	explanation += "The application used more memory than the limit you set.\n"
 	explanation += "Kubernetes killed it to prevent affecting other pods on the node.\n\n"
 
-	explanation += "🔧 HOW TO FIX:\n"
-	explanation += "1. Increase memory limits in your deployment\n"
-	explanation += "2. Fix memory leaks in your application\n"
-	explanation += "3. Optimize memory usage\n"
-	explanation += "4. Use memory profiling tools\n\n"
+	explanation += formatFixes(FixSteps(info))
 
-	explanation += "🐛 DEBUG COMMANDS:\n"
-	explanation += "-------------------\n\n"
-
-	explanation += "# 1. Check current memory limits\n"
-	explanation += fmt.Sprintf("kubectl get pod %s -n %s -o jsonpath='{.spec.containers[*].resources}'\n\n", info.PodName, info.Namespace)
-
-	explanation += "# 2. View actual memory usage (if metrics-server is installed)\n"
-	explanation += fmt.Sprintf("kubectl top pod %s -n %s\n\n", info.PodName, info.Namespace)
-
-	explanation += "# 3. Check historical resource usage\n"
-	explanation += fmt.Sprintf("kubectl describe pod %s -n %s | grep -A5 'Limits\\|Requests'\n\n", info.PodName, info.Namespace)
-
-	explanation += "# 4. View OOM events\n"
-	explanation += fmt.Sprintf("kubectl get events -n %s --field-selector reason=OOMKilling\n\n", info.Namespace)
-
-	explanation += "# 5. Check node memory pressure\n"
-	explanation += "kubectl describe nodes | grep -A5 'Memory'\n\n"
-
-	explanation += "# 6. Get pod restart count\n"
-	explanation += fmt.Sprintf("kubectl get pod %s -n %s -o jsonpath='{.status.containerStatuses[*].restartCount}'\n\n", info.PodName, info.Namespace)
-
-	explanation += "# 7. View logs before OOM kill\n"
-	explanation += fmt.Sprintf("kubectl logs %s -n %s --previous --tail=100\n\n", info.PodName, info.Namespace)
+	explanation += formatCommands(DebugCommands(info))
 
 	explanation += "📊 HOW TO INCREASE MEMORY:\n\n"
 	explanation += "Edit your deployment/pod spec:\n\n"
@@ -248,29 +397,9 @@ func explainConfigError(info FailureInfo) string {
 	explanation += "Kubernetes found an error in your pod/container configuration\n"
 	explanation += "before it could even start the container.\n\n"
 
-	explanation += "🔧 HOW TO FIX:\n"
-	explanation += "1. Verify all ConfigMaps and Secrets exist\n"
-	explanation += "2. Check volume mount paths are correct\n"
-	explanation += "3. Ensure environment variables reference valid resources\n"
-	explanation += "4. Validate YAML syntax\n\n"
+	explanation += formatFixes(FixSteps(info))
 
-	explanation += "🐛 DEBUG COMMANDS:\n"
-	explanation += "-------------------\n\n"
-
-	explanation += "# 1. Get detailed error description\n"
-	explanation += fmt.Sprintf("kubectl describe pod %s -n %s\n\n", info.PodName, info.Namespace)
-
-	explanation += "# 2. Check if referenced ConfigMaps exist\n"
-	explanation += fmt.Sprintf("kubectl get configmaps -n %s\n\n", info.Namespace)
-
-	explanation += "# 3. Check if referenced Secrets exist\n"
-	explanation += fmt.Sprintf("kubectl get secrets -n %s\n\n", info.Namespace)
-
-	explanation += "# 4. View pod YAML to find configuration issues\n"
-	explanation += fmt.Sprintf("kubectl get pod %s -n %s -o yaml\n\n", info.PodName, info.Namespace)
-
-	explanation += "# 5. Check volume mounts\n"
-	explanation += fmt.Sprintf("kubectl get pod %s -n %s -o jsonpath='{.spec.volumes}'\n\n", info.PodName, info.Namespace)
+	explanation += formatCommands(DebugCommands(info))
 
 	explanation += "📊 COMMON CAUSES:\n"
 	explanation += "- Missing ConfigMap or Secret\n"
@@ -285,11 +414,7 @@ func explainRunContainerError(info FailureInfo) string {
 	explanation := "❌ WHAT HAPPENED:\n"
 	explanation += "Kubernetes couldn't start your container.\n\n"
 
-	explanation += "🐛 DEBUG COMMANDS:\n"
-	explanation += "-------------------\n\n"
-
-	explanation += fmt.Sprintf("kubectl describe pod %s -n %s\n\n", info.PodName, info.Namespace)
-	explanation += fmt.Sprintf("kubectl get events -n %s --field-selector involvedObject.name=%s\n\n", info.Namespace, info.PodName)
+	explanation += formatCommands(DebugCommands(info))
 
 	return explanation
 }
@@ -298,11 +423,7 @@ func explainInvalidImageName(info FailureInfo) string {
 	explanation := "❌ WHAT HAPPENED:\n"
 	explanation += "The container image name is invalid or malformed.\n\n"
 
-	explanation += "🐛 DEBUG COMMANDS:\n"
-	explanation += "-------------------\n\n"
-
-	explanation += "# Check the image name\n"
-	explanation += fmt.Sprintf("kubectl get pod %s -n %s -o jsonpath='{.spec.containers[*].image}'\n\n", info.PodName, info.Namespace)
+	explanation += formatCommands(DebugCommands(info))
 
 	return explanation
 }
@@ -314,47 +435,53 @@ func explainGeneric(info FailureInfo) string {
 		explanation += "📝 ERROR MESSAGE:\n" + info.Message + "\n\n"
 	}
 
-	explanation += "🐛 DEBUG COMMANDS:\n"
-	explanation += "-------------------\n\n"
-
-	explanation += "# 1. Get detailed pod information\n"
-	explanation += fmt.Sprintf("kubectl describe pod %s -n %s\n\n", info.PodName, info.Namespace)
+	explanation += formatCommands(DebugCommands(info))
 
-	explanation += "# 2. View logs\n"
-	explanation += fmt.Sprintf("kubectl logs %s -n %s\n\n", info.PodName, info.Namespace)
+	return explanation
+}
 
-	explanation += "# 3. View previous logs (if restarted)\n"
-	explanation += fmt.Sprintf("kubectl logs %s -n %s --previous\n\n", info.PodName, info.Namespace)
+// exitCodes explains the exit codes with a well-known meaning
+var exitCodes = map[int32]string{
+	0:   "Success (but should not crash)",
+	1:   "Application error - check your code for bugs",
+	2:   "Misuse of shell command",
+	126: "Command cannot execute (permission problem?)",
+	127: "Command not found (binary doesn't exist?)",
+	130: "Terminated by Ctrl+C (SIGINT)",
+	137: "Killed by SIGKILL (usually OOM or forced termination)",
+	139: "Segmentation fault (memory access violation)",
+	143: "Terminated by SIGTERM (graceful shutdown)",
+	255: "Exit status out of range",
+}
 
-	explanation += "# 4. Check events\n"
-	explanation += fmt.Sprintf("kubectl get events -n %s --field-selector involvedObject.name=%s --sort-by='.lastTimestamp'\n\n", info.Namespace, info.PodName)
+// ExplainExitCode returns a one-line explanation of a container exit code
+func ExplainExitCode(code int32) string {
+	if meaning, ok := exitCodes[code]; ok {
+		return fmt.Sprintf("→ Exit code %d: %s", code, meaning)
+	}
+	// Shells report death by signal N as 128+N
+	if code > 128 && code < 160 {
+		return fmt.Sprintf("→ Exit code %d: Killed by signal %d", code, code-128)
+	}
+	return fmt.Sprintf("→ Exit code %d: Check application documentation", code)
+}
 
-	return explanation
+func formatFixes(fixes []string) string {
+	formatted := "🔧 HOW TO FIX:\n"
+	for i, fix := range fixes {
+		formatted += fmt.Sprintf("%d. %s\n", i+1, fix)
+	}
+	return formatted + "\n"
 }
 
-func explainExitCode(code int32) string {
-	switch code {
-	case 0:
-		return "→ Exit code 0: Success (but should not crash)"
-	case 1:
-		return "→ Exit code 1: Application error - check your code for bugs"
-	case 2:
-		return "→ Exit code 2: Misuse of shell command"
-	case 126:
-		return "→ Exit code 126: Command cannot execute (permission problem?)"
-	case 127:
-		return "→ Exit code 127: Command not found (binary doesn't exist?)"
-	case 130:
-		return "→ Exit code 130: Terminated by Ctrl+C (SIGINT)"
-	case 137:
-		return "→ Exit code 137: Killed by SIGKILL (usually OOM or forced termination)"
-	case 139:
-		return "→ Exit code 139: Segmentation fault (memory access violation)"
-	case 143:
-		return "→ Exit code 143: Terminated by SIGTERM (graceful shutdown)"
-	case 255:
-		return "→ Exit code 255: Exit status out of range"
-	default:
-		return fmt.Sprintf("→ Exit code %d: Check application documentation", code)
+func formatCommands(commands []DebugCommand) string {
+	formatted := "🐛 DEBUG COMMANDS:\n"
+	formatted += "-------------------\n\n"
+	for i, cmd := range commands {
+		if cmd.Description != "" {
+			formatted += fmt.Sprintf("# %d. %s\n", i+1, cmd.Description)
+		}
+		formatted += cmd.Command + "\n\n"
 	}
-}
\ No newline at end of file
+	return formatted
+}
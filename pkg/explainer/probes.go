@@ -0,0 +1,205 @@
+package explainer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProbeFailure describes a failing liveness, readiness or startup probe, from
+// the container spec and the kubelet's events
+type ProbeFailure struct {
+	Type string `json:"type"` // "liveness", "readiness" or "startup"
+
+	// Action is how the probe checks: "HTTP GET", "TCP", "gRPC" or "exec"
+	Action  string `json:"action"`
+	Path    string `json:"path,omitempty"`
+	Port    string `json:"port,omitempty"`
+	Command string `json:"command,omitempty"`
+
+	InitialDelaySeconds int32 `json:"initialDelaySeconds"`
+	TimeoutSeconds      int32 `json:"timeoutSeconds"`
+	PeriodSeconds       int32 `json:"periodSeconds"`
+	FailureThreshold    int32 `json:"failureThreshold"`
+
+	// HasStartupProbe tells whether the container also has a startup probe,
+	// which holds off liveness checks until it succeeds
+	HasStartupProbe bool `json:"hasStartupProbe,omitempty"`
+
+	// Output is what the latest failed check reported, and Failures how many
+	// checks failed according to the events
+	Output   string `json:"output,omitempty"`
+	Failures int32  `json:"failures,omitempty"`
+
+	// RanSeconds is how long the last instance ran before it was killed; 0
+	// when unknown
+	RanSeconds int32 `json:"ranSeconds,omitempty"`
+}
+
+// probeReasons maps the failure reason of each probe type
+var probeReasons = map[string]string{
+	"liveness":  "LivenessProbeFailed",
+	"readiness": "ReadinessProbeFailed",
+	"startup":   "StartupProbeFailed",
+}
+
+// ProbeReason returns the failure reason for a probe type
+func ProbeReason(probeType string) string {
+	return probeReasons[probeType]
+}
+
+// Target describes what the probe checks, e.g. "HTTP GET :8080/healthz"
+func (p *ProbeFailure) Target() string {
+	switch p.Action {
+	case "exec":
+		return "exec: " + p.Command
+	case "HTTP GET", "HTTPS GET":
+		return fmt.Sprintf("%s :%s%s", p.Action, p.Port, p.Path)
+	default:
+		return fmt.Sprintf("%s :%s", p.Action, p.Port)
+	}
+}
+
+// budget is how long the probe lets a new container go without a successful
+// check before it acts
+func (p *ProbeFailure) budget() int32 {
+	return p.InitialDelaySeconds + p.FailureThreshold*p.PeriodSeconds
+}
+
+// Warnings points out probe settings that likely cause the failure
+func (p *ProbeFailure) Warnings() []string {
+	var warnings []string
+	output := strings.ToLower(p.Output)
+	kills := p.Type == "liveness" || p.Type == "startup"
+
+	// Killed within the probe's budget while nothing answered yet: the app
+	// was still starting, so it takes at least as long as it ran
+	refused := strings.Contains(output, "connection refused") || strings.Contains(output, "no such file")
+	if kills && refused && p.RanSeconds > 0 && p.RanSeconds <= p.budget()+p.PeriodSeconds {
+		switch {
+		case p.Type == "startup":
+			warnings = append(warnings, fmt.Sprintf(
+				"The container ran %ds without ever answering its startup probe, which gives up after %ds (initialDelaySeconds + failureThreshold × periodSeconds): raise failureThreshold to cover the slowest start",
+				p.RanSeconds, p.budget()))
+		case !p.HasStartupProbe:
+			warnings = append(warnings, fmt.Sprintf(
+				"initialDelaySeconds (%ds) is shorter than the observed startup time: the container ran %ds without ever answering the probe, which gives up after %ds (initialDelaySeconds + failureThreshold × periodSeconds)",
+				p.InitialDelaySeconds, p.RanSeconds, p.budget()))
+		}
+	}
+
+	timedOut := strings.Contains(output, "timeout") || strings.Contains(output, "deadline exceeded") || strings.Contains(output, "timed out")
+	if timedOut && p.TimeoutSeconds <= 1 {
+		warnings = append(warnings, fmt.Sprintf(
+			"timeoutSeconds is %ds and the check timed out: an endpoint that is only slow fails the probe", p.TimeoutSeconds))
+	}
+
+	if p.FailureThreshold == 1 {
+		consequence := "mark the pod unready"
+		if kills {
+			consequence = "restart the container"
+		}
+		warnings = append(warnings, "failureThreshold is 1: a single failed check is enough to "+consequence)
+	}
+
+	return warnings
+}
+
+func explainProbeFailure(info FailureInfo) string {
+	explanation := "❌ WHAT HAPPENED:\n"
+	switch info.Reason {
+	case "LivenessProbeFailed":
+		explanation += "Kubernetes keeps killing your container because its liveness probe fails.\n\n"
+	case "StartupProbeFailed":
+		explanation += "Kubernetes keeps killing your container because it doesn't pass its startup probe in time.\n\n"
+	default:
+		explanation += "Your container is running but never becomes ready, because its readiness probe fails.\n\n"
+	}
+
+	explanation += "🤔 WHAT THIS MEANS:\n"
+	switch info.Reason {
+	case "ReadinessProbeFailed":
+		explanation += "The container is not restarted, but it gets no traffic from Services\n"
+		explanation += "and rollouts wait for it, until the probe passes.\n\n"
+	default:
+		explanation += "The process may be fine: the probe decides it is unhealthy and the kubelet\n"
+		explanation += "restarts it. This looks like an ordinary crash loop, often with exit code 137.\n\n"
+	}
+
+	if probe := info.Probe; probe != nil {
+		explanation += "🩺 PROBE:\n"
+		explanation += fmt.Sprintf("%sProbe: %s\n", probe.Type, probe.Target())
+		explanation += fmt.Sprintf("initialDelaySeconds: %d, timeoutSeconds: %d, periodSeconds: %d, failureThreshold: %d\n",
+			probe.InitialDelaySeconds, probe.TimeoutSeconds, probe.PeriodSeconds, probe.FailureThreshold)
+		if probe.Failures > 0 {
+			explanation += fmt.Sprintf("Failed checks: %d\n", probe.Failures)
+		}
+		explanation += "\n"
+
+		if probe.Output != "" {
+			explanation += "📝 PROBE OUTPUT:\n"
+			explanation += probe.Output + "\n\n"
+		}
+
+		if warnings := probe.Warnings(); len(warnings) > 0 {
+			explanation += "⚠️  LIKELY MISCONFIGURATION:\n"
+			for _, warning := range warnings {
+				explanation += "- " + warning + "\n"
+			}
+			explanation += "\n"
+		}
+	}
+
+	if info.LastLog != "" {
+		explanation += "📝 LAST LOG LINES:\n"
+		explanation += info.LastLog + "\n\n"
+	}
+
+	explanation += formatFixes(FixSteps(info))
+
+	explanation += formatCommands(DebugCommands(info))
+
+	explanation += "📊 COMMON CAUSES:\n"
+	explanation += "- Slow startup with a short initialDelaySeconds and no startupProbe\n"
+	explanation += "- Wrong port or path in the probe\n"
+	explanation += "- timeoutSeconds too short for a busy endpoint\n"
+	explanation += "- Health endpoint checking dependencies (database, other services)\n"
+	explanation += "- Application deadlocked or overloaded\n"
+
+	return explanation
+}
+
+// probeCommands suggests the kubectl commands for investigating a probe failure
+func probeCommands(info FailureInfo) []DebugCommand {
+	pod, ns, container := info.PodName, info.Namespace, info.ContainerName
+
+	probeType := strings.TrimSuffix(strings.ToLower(info.Reason), "probefailed")
+	if info.Probe != nil {
+		probeType = info.Probe.Type
+	}
+
+	commands := []DebugCommand{
+		{"Show the probe definition", fmt.Sprintf("kubectl get pod %s -n %s -o jsonpath='{.spec.containers[?(@.name==\"%s\")].%sProbe}'", pod, ns, container, probeType)},
+		{"Show the probe failures", fmt.Sprintf("kubectl get events -n %s --field-selector involvedObject.name=%s,reason=Unhealthy", ns, pod)},
+	}
+
+	if probe := info.Probe; probe != nil {
+		switch probe.Action {
+		case "HTTP GET", "HTTPS GET":
+			scheme := strings.ToLower(strings.TrimSuffix(probe.Action, " GET"))
+			commands = append(commands, DebugCommand{"Call the endpoint from inside the container (if the image has wget)",
+				fmt.Sprintf("kubectl exec %s -n %s -c %s -- wget -qO- %s://localhost:%s%s", pod, ns, container, scheme, probe.Port, probe.Path)})
+		case "exec":
+			commands = append(commands, DebugCommand{"Run the probe command yourself",
+				fmt.Sprintf("kubectl exec %s -n %s -c %s -- %s", pod, ns, container, probe.Command)})
+		}
+	}
+
+	if info.Reason == "ReadinessProbeFailed" {
+		commands = append(commands, DebugCommand{"View recent logs", fmt.Sprintf("kubectl logs %s -n %s -c %s --tail=50", pod, ns, container)})
+	} else {
+		commands = append(commands, DebugCommand{"View logs of the killed instance", fmt.Sprintf("kubectl logs %s -n %s -c %s --previous --tail=50", pod, ns, container)})
+	}
+	commands = append(commands, DebugCommand{"Get detailed pod information", fmt.Sprintf("kubectl describe pod %s -n %s", pod, ns)})
+
+	return commands
+}
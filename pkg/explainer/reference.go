@@ -0,0 +1,61 @@
+package explainer
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Reason is a container failure reason the explainer knows in detail
+type Reason struct {
+	Name        string
+	Description string
+}
+
+// reasons is the reference list shown by 'explain list'; keep it in step with
+// the cases of Explain
+var reasons = []Reason{
+	{"CrashLoopBackOff", "The container keeps crashing and Kubernetes backs off restarting it"},
+	{"ImagePullBackOff", "The image can't be pulled and Kubernetes backs off retrying"},
+	{"ErrImagePull", "The last attempt to pull the image failed"},
+	{"OOMKilled", "The container used more memory than its limit and was killed"},
+	{"CreateContainerConfigError", "The container can't be created, usually a missing ConfigMap or Secret"},
+	{"RunContainerError", "The runtime created the container but couldn't start it"},
+	{"InvalidImageName", "The image reference can't be parsed"},
+	{"LivenessProbeFailed", "The kubelet keeps killing the container because its liveness probe fails"},
+	{"StartupProbeFailed", "The kubelet kills the container because it doesn't pass its startup probe in time"},
+	{"ReadinessProbeFailed", "The container runs but its readiness probe fails, so it never becomes ready"},
+	{"Evicted", "The kubelet evicted the pod, usually because its node ran low on memory or disk"},
+}
+
+// Reasons lists every failure reason with a dedicated explanation
+func Reasons() []Reason {
+	return slices.Clone(reasons)
+}
+
+// LookupReason finds a known reason, ignoring case
+func LookupReason(name string) (Reason, bool) {
+	for _, reason := range reasons {
+		if strings.EqualFold(reason.Name, name) {
+			return reason, true
+		}
+	}
+	return Reason{}, false
+}
+
+// ExitCodes lists the exit codes with a well-known meaning, in order
+func ExitCodes() []int32 {
+	return slices.Sorted(maps.Keys(exitCodes))
+}
+
+// Placeholder is a failure with placeholder names, for explaining a reason
+// without a pod
+func Placeholder(reason string, exitCode int32) FailureInfo {
+	return FailureInfo{
+		PodName:       "<pod>",
+		Namespace:     "<namespace>",
+		ContainerName: "<container>",
+		Reason:        reason,
+		ExitCode:      exitCode,
+	}
+}
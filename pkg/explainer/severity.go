@@ -0,0 +1,66 @@
+package explainer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity ranks how urgently a failure needs attention
+type Severity int
+
+const (
+	// SeverityInfo is a container that was stopped on purpose, e.g. by SIGTERM
+	SeverityInfo Severity = iota
+	// SeverityWarning is a container that exited with an error once
+	SeverityWarning
+	// SeverityCritical is a container that cannot run: crash loops, OOM kills,
+	// image pull and configuration errors
+	SeverityCritical
+)
+
+var severityNames = map[Severity]string{
+	SeverityInfo:     "info",
+	SeverityWarning:  "warning",
+	SeverityCritical: "critical",
+}
+
+func (s Severity) String() string {
+	if name, ok := severityNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// ParseSeverity parses "info", "warning" or "critical"
+func ParseSeverity(name string) (Severity, error) {
+	for s, n := range severityNames {
+		if strings.EqualFold(name, n) {
+			return s, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown severity %q, expected info, warning or critical", name)
+}
+
+// SeverityOf rates a failure
+func SeverityOf(info FailureInfo) Severity {
+	switch info.Reason {
+	case "CrashLoopBackOff", "OOMKilled", "ImagePullBackOff", "ErrImagePull",
+		"CreateContainerConfigError", "InvalidImageName", "RunContainerError",
+		"LivenessProbeFailed", "StartupProbeFailed":
+		return SeverityCritical
+	case "ReadinessProbeFailed":
+		return SeverityWarning
+	case "Evicted":
+		// Controllers replace a single evicted pod; a node shedding many is worse
+		if nodeLevel(info) {
+			return SeverityCritical
+		}
+		return SeverityWarning
+	}
+
+	// Interrupted or asked to shut down, rather than failing by itself
+	if info.ExitCode == 130 || info.ExitCode == 143 {
+		return SeverityInfo
+	}
+	return SeverityWarning
+}
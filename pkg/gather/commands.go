@@ -0,0 +1,193 @@
+package gather
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/explainer"
+)
+
+// Commands runs debug commands with kubectl and stores their output
+type Commands struct {
+	// Kubectl is the kubectl binary
+	Kubectl string
+
+	// Args go before every command's own arguments, e.g. --context
+	Args []string
+
+	// Timeout bounds each command (default 30s)
+	Timeout time.Duration
+}
+
+// NewCommands finds kubectl on the PATH
+func NewCommands(args []string) (*Commands, error) {
+	kubectl, err := exec.LookPath("kubectl")
+	if err != nil {
+		return nil, err
+	}
+	return &Commands{Kubectl: kubectl, Args: args}, nil
+}
+
+// readOnlyVerbs are the kubectl verbs safe to run unattended
+var readOnlyVerbs = []string{"get", "describe", "logs", "top"}
+
+// skip says why a suggested command is not run unattended, or "" when it is
+// safe: it must only read, finish on its own and not print Secret values.
+// Pipes are dropped before this check, so the archive keeps the full output.
+func skip(args []string) string {
+	if len(args) < 2 || args[0] != "kubectl" {
+		return "not a kubectl command"
+	}
+	if !slices.Contains(readOnlyVerbs, args[1]) {
+		return fmt.Sprintf("'kubectl %s' is not read-only", args[1])
+	}
+
+	for _, arg := range args[2:] {
+		switch {
+		case arg == "-f" || arg == "--follow" || arg == "-w" || arg == "--watch" || strings.HasPrefix(arg, "--watch"):
+			return "streams until interrupted"
+		case arg == "-i" || arg == "-t" || arg == "-it" || arg == "--stdin" || arg == "--tty":
+			return "interactive"
+		}
+	}
+
+	// Flags may come before the resource, as in 'kubectl get -o yaml secret x'
+	if args[1] == "get" && slices.ContainsFunc(args[2:], namesSecrets) &&
+		slices.ContainsFunc(args[2:], printsObjects) {
+		return "would print Secret values"
+	}
+	return ""
+}
+
+// printsObjects reports whether a 'kubectl get' flag prints whole objects,
+// values included
+func printsObjects(arg string) bool {
+	return strings.HasPrefix(arg, "-o") || strings.HasPrefix(arg, "--output") || strings.HasPrefix(arg, "--template")
+}
+
+// namesSecrets reports whether a kubectl argument names Secrets, e.g.
+// "secret", "secrets/db" or "configmaps,secrets". Flag values such as a
+// namespace called "secrets" match too, which only skips a safe command.
+func namesSecrets(arg string) bool {
+	if strings.HasPrefix(arg, "-") {
+		return false
+	}
+	for _, resource := range strings.Split(arg, ",") {
+		resource, _, _ = strings.Cut(resource, "/")
+		resource, _, _ = strings.Cut(resource, ".")
+		if resource == "secret" || resource == "secrets" {
+			return true
+		}
+	}
+	return false
+}
+
+// split breaks a command line into arguments, honouring quotes, and drops
+// everything from the first pipe on
+func split(command string) []string {
+	var (
+		args    []string
+		current strings.Builder
+		quote   rune
+		inArg   bool
+	)
+	for _, r := range command {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == '|':
+			if inArg {
+				args = append(args, current.String())
+			}
+			return args
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9._]+`)
+
+// run runs every safe command once and writes its output, plus an index of
+// what was run and skipped, under dir
+func (c *Commands) run(ctx context.Context, a *Archive, dir string, commands []explainer.DebugCommand) error {
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	var (
+		index strings.Builder
+		seen  []string
+		files int
+	)
+	for _, command := range commands {
+		args := split(command.Command)
+		line := strings.Join(args, " ")
+		if slices.Contains(seen, line) {
+			continue
+		}
+		seen = append(seen, line)
+
+		if reason := skip(args); reason != "" {
+			fmt.Fprintf(&index, "SKIPPED  %s (%s)\n", command.Command, reason)
+			continue
+		}
+
+		files++
+		name := fmt.Sprintf("%02d-%s.txt", files, strings.Trim(unsafeFileChars.ReplaceAllString(strings.Join(args[1:], "-"), "-"), "-"))
+		if len(name) > 100 {
+			name = name[:96] + ".txt"
+		}
+
+		cmdCtx, cancel := context.WithTimeout(ctx, timeout)
+		cmd := exec.CommandContext(cmdCtx, c.Kubectl, append(slices.Clone(c.Args), args[1:]...)...)
+		var output bytes.Buffer
+		cmd.Stdout = &output
+		cmd.Stderr = &output
+		err := cmd.Run()
+		cancel()
+
+		status := "OK"
+		var exitErr *exec.ExitError
+		switch {
+		case errors.Is(cmdCtx.Err(), context.DeadlineExceeded):
+			status = "TIMEOUT"
+		case errors.As(err, &exitErr):
+			status = fmt.Sprintf("EXIT %d", exitErr.ExitCode())
+		case err != nil:
+			status = "FAILED"
+			fmt.Fprintf(&output, "%v\n", err)
+		}
+		fmt.Fprintf(&index, "%-8s %s -> %s\n", status, line, name)
+
+		content := fmt.Sprintf("$ %s\n# %s\n\n%s", line, command.Description, output.String())
+		if err := a.add(dir+name, []byte(content)); err != nil {
+			return err
+		}
+	}
+
+	return a.add(dir+"index.txt", []byte(index.String()))
+}
@@ -0,0 +1,61 @@
+package gather
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSkip(t *testing.T) {
+	tests := []struct {
+		command string
+		safe    bool
+	}{
+		{"kubectl describe pod api -n shop", true},
+		{"kubectl logs api -c app --previous", true},
+		{"kubectl top pod api", true},
+		{"kubectl get events -n shop --field-selector involvedObject.name=api", true},
+		{"kubectl get secret db -n shop", true},
+		{"kubectl describe secret db", true},
+		{"kubectl get configmap app -o yaml", true},
+		{"kubectl get secret db -o yaml", false},
+		{"kubectl get -o yaml secret db", false},
+		{"kubectl get -n shop secrets/db -ojson", false},
+		{"kubectl get configmaps,secrets --output=json", false},
+		{"kubectl get secret db --template={{.data}}", false},
+		{"kubectl delete pod api", false},
+		{"kubectl exec -it api -- sh", false},
+		{"kubectl logs -f api", false},
+		{"kubectl get pods --watch", false},
+		{"helm list", false},
+		{"kubectl", false},
+	}
+
+	for _, tt := range tests {
+		reason := skip(split(tt.command))
+		if safe := reason == ""; safe != tt.safe {
+			t.Errorf("skip(%q) = %q, want safe %v", tt.command, reason, tt.safe)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+	}{
+		{"kubectl get pods", []string{"kubectl", "get", "pods"}},
+		{"  kubectl\tget   pods ", []string{"kubectl", "get", "pods"}},
+		{`kubectl get pod api -o jsonpath='{.status.phase}'`, []string{"kubectl", "get", "pod", "api", "-o", "jsonpath={.status.phase}"}},
+		{`kubectl logs api --selector "app=web tier"`, []string{"kubectl", "logs", "api", "--selector", "app=web tier"}},
+		{"kubectl get events | grep api", []string{"kubectl", "get", "events"}},
+		{"kubectl get events| grep api", []string{"kubectl", "get", "events"}},
+		{`kubectl get pod -o jsonpath='{.a|b}'`, []string{"kubectl", "get", "pod", "-o", "jsonpath={.a|b}"}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		if got := split(tt.command); !slices.Equal(got, tt.want) {
+			t.Errorf("split(%q) = %q, want %q", tt.command, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,388 @@
+// Package gather collects everything needed to escalate a pod failure into a
+// single tar.gz archive
+package gather
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/explainer"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/inspect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+)
+
+// Options tunes what is collected
+type Options struct {
+	// LogLines is how many lines of each log to keep (default 1000)
+	LogLines int64
+
+	// Commands runs the explainers' read-only debug commands with kubectl
+	// (optional)
+	Commands *Commands
+}
+
+// Archive is a must-gather bundle being written. Every file lives under a
+// single timestamped directory.
+type Archive struct {
+	file *os.File
+	gz   *gzip.Writer
+	tw   *tar.Writer
+	root string
+	now  time.Time
+
+	files    int
+	pods     []string
+	problems []string
+}
+
+// Create starts an archive at path whose files are stored under root
+func Create(path, root string) (*Archive, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create archive: %w", err)
+	}
+
+	gz := gzip.NewWriter(file)
+	return &Archive{file: file, gz: gz, tw: tar.NewWriter(gz), root: root, now: time.Now()}, nil
+}
+
+// Files returns how many files were added so far
+func (a *Archive) Files() int {
+	return a.files
+}
+
+// Problems lists what could not be collected
+func (a *Archive) Problems() []string {
+	return a.problems
+}
+
+func (a *Archive) problem(what string, err error) {
+	a.problems = append(a.problems, fmt.Sprintf("could not %s: %v", what, err))
+}
+
+// add writes one file to the archive
+func (a *Archive) add(name string, data []byte) error {
+	header := &tar.Header{
+		Name:    path.Join(a.root, name),
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: a.now,
+	}
+	if err := a.tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s to archive: %w", name, err)
+	}
+	if _, err := a.tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s to archive: %w", name, err)
+	}
+	a.files++
+	return nil
+}
+
+// addObject writes an object as YAML, without the noise of managed fields
+func (a *Archive) addObject(name string, object runtime.Object) error {
+	data, err := toYAML(object)
+	if err != nil {
+		a.problem("encode "+name, err)
+		return nil
+	}
+	return a.add(name, data)
+}
+
+func toYAML(object runtime.Object) ([]byte, error) {
+	object = object.DeepCopyObject()
+
+	// Typed clients drop apiVersion and kind
+	if kinds, _, err := scheme.Scheme.ObjectKinds(object); err == nil && len(kinds) > 0 {
+		object.GetObjectKind().SetGroupVersionKind(kinds[0])
+	}
+	if meta, ok := object.(metav1.Object); ok {
+		meta.SetManagedFields(nil)
+	}
+	return yaml.Marshal(object)
+}
+
+// Namespace adds every event and pod of a namespace
+func (a *Archive) Namespace(ctx context.Context, clientset kubernetes.Interface, namespace string) error {
+	events, err := clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		a.problem("list events in "+namespace, err)
+	} else if err := a.addObject("namespace/events.yaml", events); err != nil {
+		return err
+	}
+
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		a.problem("list pods in "+namespace, err)
+		return nil
+	}
+	return a.addObject("namespace/pods.yaml", pods)
+}
+
+// Pod adds everything known about one pod under pods/NAME/. Only failing to
+// write the archive is an error; anything that can't be collected, even the
+// pod itself if it is gone, is listed in problems.txt.
+func (a *Archive) Pod(ctx context.Context, clientset kubernetes.Interface, namespace, name string, opts Options) error {
+	if opts.LogLines == 0 {
+		opts.LogLines = 1000
+	}
+
+	report, err := inspect.Pod(ctx, clientset, namespace, name, inspect.Options{LogLines: opts.LogLines})
+	if err != nil {
+		// e.g. a crash-looping Job pod deleted since it was found
+		a.problem("gather pod "+name, err)
+		return nil
+	}
+	a.pods = append(a.pods, namespace+"/"+name)
+	for _, problem := range report.Problems {
+		a.problems = append(a.problems, name+": "+problem)
+	}
+
+	dir := "pods/" + name + "/"
+	pod := report.Pod
+
+	// ===== THE DETECTIVE'S OWN DIAGNOSIS =====
+	var text bytes.Buffer
+	report.Write(&text)
+	if err := a.add(dir+"diagnosis.txt", text.Bytes()); err != nil {
+		return err
+	}
+
+	diagnoses := make([]diagnosis, len(report.Failures))
+	for i, info := range report.Failures {
+		diagnoses[i] = diagnosis{Failure: info, Severity: explainer.SeverityOf(info).String(), Diagnosis: explainer.Diagnose(info)}
+	}
+	data, err := json.MarshalIndent(diagnoses, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := a.add(dir+"diagnosis.json", data); err != nil {
+		return err
+	}
+
+	// ===== OBJECTS =====
+	if err := a.addObject(dir+"pod.yaml", pod); err != nil {
+		return err
+	}
+
+	for _, owner := range owners(ctx, clientset, namespace, pod.OwnerReferences, a.problem) {
+		meta := owner.(metav1.Object)
+		kind := strings.ToLower(owner.GetObjectKind().GroupVersionKind().Kind)
+		if err := a.addObject(fmt.Sprintf("%sworkload/%s-%s.yaml", dir, kind, meta.GetName()), owner); err != nil {
+			return err
+		}
+	}
+
+	if report.Node != nil {
+		if err := a.addObject(dir+"node.yaml", report.Node); err != nil {
+			return err
+		}
+	}
+
+	if len(report.Events) > 0 {
+		if err := a.add(dir+"events.yaml", mustYAML(report.Events)); err != nil {
+			return err
+		}
+	}
+
+	// ===== LOGS =====
+	for _, log := range report.Logs {
+		file := dir + "logs/" + log.Container
+		if log.Previous {
+			file += ".previous"
+		}
+		content := log.Log
+		if log.Err != nil {
+			content = fmt.Sprintf("could not fetch log: %v\n", log.Err)
+		}
+		if err := a.add(file+".log", []byte(content)); err != nil {
+			return err
+		}
+	}
+
+	// ===== CONFIGMAPS AND SECRETS =====
+	if err := a.references(ctx, clientset, dir, report.References, namespace); err != nil {
+		return err
+	}
+
+	// ===== DEBUG COMMANDS =====
+	if opts.Commands != nil {
+		var commands []explainer.DebugCommand
+		for _, info := range report.Failures {
+			commands = append(commands, explainer.DebugCommands(info)...)
+		}
+		commands = append(commands,
+			explainer.DebugCommand{Description: "Describe the pod", Command: fmt.Sprintf("kubectl describe pod %s -n %s", name, namespace)})
+		if pod.Spec.NodeName != "" {
+			commands = append(commands,
+				explainer.DebugCommand{Description: "Describe the node", Command: "kubectl describe node " + pod.Spec.NodeName})
+		}
+		if err := opts.Commands.run(ctx, a, dir+"commands/", commands); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diagnosis is one entry of diagnosis.json
+type diagnosis struct {
+	Failure   explainer.FailureInfo `json:"failure"`
+	Severity  string                `json:"severity"`
+	Diagnosis explainer.Diagnosis   `json:"diagnosis"`
+}
+
+func mustYAML(v any) []byte {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return []byte(fmt.Sprintf("could not encode: %v\n", err))
+	}
+	return data
+}
+
+// secretKeys is what is kept of a Secret: never its values
+type secretKeys struct {
+	Note string   `json:"note"`
+	Name string   `json:"name"`
+	Type string   `json:"type"`
+	Keys []string `json:"keys"`
+}
+
+// references adds the ConfigMaps the pod uses and the key names of its Secrets
+func (a *Archive) references(ctx context.Context, clientset kubernetes.Interface, dir string, references []inspect.Reference, namespace string) error {
+	var done []string
+	for _, ref := range references {
+		id := ref.Kind + "/" + ref.Name
+		if slices.Contains(done, id) {
+			continue
+		}
+		done = append(done, id)
+
+		switch ref.Kind {
+		case "ConfigMap":
+			configMap, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				// diagnosis.txt already reports it missing
+				continue
+			}
+			if err != nil {
+				a.problem("get ConfigMap "+ref.Name, err)
+				continue
+			}
+			if err := a.addObject(dir+"configmaps/"+ref.Name+".yaml", configMap); err != nil {
+				return err
+			}
+
+		case "Secret":
+			secret, err := clientset.CoreV1().Secrets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				a.problem("get Secret "+ref.Name, err)
+				continue
+			}
+			redacted := secretKeys{Note: "values redacted, only key names are kept", Name: secret.Name, Type: string(secret.Type)}
+			for key := range secret.Data {
+				redacted.Keys = append(redacted.Keys, key)
+			}
+			for key := range secret.StringData {
+				redacted.Keys = append(redacted.Keys, key)
+			}
+			slices.Sort(redacted.Keys)
+			if err := a.add(dir+"secrets/"+ref.Name+".yaml", mustYAML(redacted)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// owners returns the owner chain of an object, e.g. the ReplicaSet and
+// Deployment of a pod
+func owners(ctx context.Context, clientset kubernetes.Interface, namespace string, refs []metav1.OwnerReference, problem func(string, error)) []runtime.Object {
+	var found []runtime.Object
+	for _, ref := range refs {
+		var (
+			owner runtime.Object
+			next  []metav1.OwnerReference
+			err   error
+		)
+		get := metav1.GetOptions{}
+		switch ref.Kind {
+		case "ReplicaSet":
+			replicaSet, getErr := clientset.AppsV1().ReplicaSets(namespace).Get(ctx, ref.Name, get)
+			owner, next, err = replicaSet, replicaSet.OwnerReferences, getErr
+		case "Deployment":
+			deployment, getErr := clientset.AppsV1().Deployments(namespace).Get(ctx, ref.Name, get)
+			owner, next, err = deployment, deployment.OwnerReferences, getErr
+		case "StatefulSet":
+			statefulSet, getErr := clientset.AppsV1().StatefulSets(namespace).Get(ctx, ref.Name, get)
+			owner, next, err = statefulSet, statefulSet.OwnerReferences, getErr
+		case "DaemonSet":
+			daemonSet, getErr := clientset.AppsV1().DaemonSets(namespace).Get(ctx, ref.Name, get)
+			owner, next, err = daemonSet, daemonSet.OwnerReferences, getErr
+		case "Job":
+			job, getErr := clientset.BatchV1().Jobs(namespace).Get(ctx, ref.Name, get)
+			owner, next, err = job, job.OwnerReferences, getErr
+		case "CronJob":
+			cronJob, getErr := clientset.BatchV1().CronJobs(namespace).Get(ctx, ref.Name, get)
+			owner, next, err = cronJob, cronJob.OwnerReferences, getErr
+		default:
+			// Custom controllers; the owner reference in pod.yaml names them
+			continue
+		}
+		if err != nil {
+			problem("get "+ref.Kind+" "+ref.Name, err)
+			continue
+		}
+
+		// Set the kind now so the file can be named after it
+		if kinds, _, err := scheme.Scheme.ObjectKinds(owner); err == nil && len(kinds) > 0 {
+			owner.GetObjectKind().SetGroupVersionKind(kinds[0])
+		}
+		found = append(found, owner)
+		found = append(found, owners(ctx, clientset, namespace, next, problem)...)
+	}
+	return found
+}
+
+// Close writes the summary and problem list and finishes the archive
+func (a *Archive) Close() error {
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "Collected by k8s-pod-detective gather at %s\n\n", a.now.UTC().Format(time.RFC3339))
+	if len(a.pods) > 0 {
+		fmt.Fprintf(&summary, "Pods:\n")
+		for _, pod := range a.pods {
+			fmt.Fprintf(&summary, "  %s\n", pod)
+		}
+	} else {
+		fmt.Fprintf(&summary, "No failing pods found.\n")
+	}
+	fmt.Fprintf(&summary, "\nSecrets are included as key names only; their values were never read into the archive.\n")
+	err := a.add("summary.txt", []byte(summary.String()))
+
+	if err == nil && len(a.problems) > 0 {
+		err = a.add("problems.txt", []byte(strings.Join(a.problems, "\n")+"\n"))
+	}
+
+	for _, closeErr := range []error{a.tw.Close(), a.gz.Close(), a.file.Close()} {
+		if err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,136 @@
+package gather
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const password = "hunter2"
+
+// readArchive returns every file of a finished archive by name
+func readArchive(t *testing.T, path string) map[string]string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+
+	files := make(map[string]string)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		if err != nil {
+			t.Fatalf("archive is not a complete tar.gz: %v", err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[header.Name] = string(data)
+	}
+}
+
+func TestPodKeepsOnlySecretKeyNames(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:    "app",
+			EnvFrom: []corev1.EnvFromSource{{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "app"}}}},
+			Env: []corev1.EnvVar{{Name: "DB_PASSWORD", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "db"},
+				Key:                  "password",
+			}}}},
+		}}},
+	}
+	clientset := fake.NewClientset(pod,
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "shop"}, Data: map[string]string{"LOG_LEVEL": "debug"}},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop"},
+			Type:       corev1.SecretTypeOpaque,
+			Data:       map[string][]byte{"password": []byte(password), "user": []byte("shop")},
+		},
+	)
+
+	path := filepath.Join(t.TempDir(), "gather.tar.gz")
+	archive, err := Create(path, "gather")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := archive.Namespace(ctx, clientset, "shop"); err != nil {
+		t.Fatal(err)
+	}
+	if err := archive.Pod(ctx, clientset, "shop", "api", Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files := readArchive(t, path)
+	secret, ok := files["gather/pods/api/secrets/db.yaml"]
+	if !ok {
+		t.Fatalf("no secrets/db.yaml in %v", slices.Sorted(maps.Keys(files)))
+	}
+	for _, key := range []string{"password", "user"} {
+		if !strings.Contains(secret, "- "+key) {
+			t.Errorf("secrets/db.yaml lacks key %s:\n%s", key, secret)
+		}
+	}
+	if !strings.Contains(files["gather/pods/api/configmaps/app.yaml"], "LOG_LEVEL: debug") {
+		t.Errorf("configmaps/app.yaml lacks its data:\n%s", files["gather/pods/api/configmaps/app.yaml"])
+	}
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(password))
+	for name, content := range files {
+		if strings.Contains(content, password) || strings.Contains(content, encoded) {
+			t.Errorf("%s holds the Secret's value", name)
+		}
+	}
+}
+
+func TestPodGoneIsAProblem(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gather.tar.gz")
+	archive, err := Create(path, "gather")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Found failing by the scan, deleted before it was gathered
+	if err := archive.Pod(context.Background(), fake.NewClientset(), "shop", "migrate-x7k2p", Options{}); err != nil {
+		t.Fatalf("Pod() = %v, want the missing pod listed as a problem", err)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files := readArchive(t, path)
+	if !strings.Contains(files["gather/problems.txt"], "migrate-x7k2p") {
+		t.Errorf("problems.txt doesn't list the missing pod:\n%s", files["gather/problems.txt"])
+	}
+	if _, ok := files["gather/summary.txt"]; !ok {
+		t.Errorf("no summary.txt in %v", slices.Sorted(maps.Keys(files)))
+	}
+}
@@ -0,0 +1,63 @@
+package health
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"time"
+)
+
+// Source reports the detector's progress
+type Source interface {
+	// LastSync returns when the last poll completed, zero before the first one
+	LastSync() time.Time
+}
+
+// Config configures the health endpoints
+type Config struct {
+	// MaxStaleness is how long the detector may go without completing a poll
+	// before /healthz fails, typically a few poll intervals
+	MaxStaleness time.Duration
+
+	// Pprof mounts the net/http/pprof handlers under /debug/pprof/
+	Pprof bool
+}
+
+// NewMux serves /healthz, /readyz and optionally /debug/pprof/
+func NewMux(src Source, config Config) *http.ServeMux {
+	started := time.Now()
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		// Before the first sync, measure from startup so a slow first poll
+		// doesn't get the detector restarted
+		progress := src.LastSync()
+		if progress.IsZero() {
+			progress = started
+		}
+
+		if age := time.Since(progress); age > config.MaxStaleness {
+			http.Error(w, fmt.Sprintf("no progress for %s (limit %s)", age.Round(time.Second), config.MaxStaleness), http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if src.LastSync().IsZero() {
+			http.Error(w, "pods not synced yet", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+
+	if config.Pprof {
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	}
+
+	return mux
+}
@@ -0,0 +1,228 @@
+// Package history keeps the incidents the detector reported and summarizes
+// them over a time window
+package history
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/explainer"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/notifier"
+)
+
+// Incident is one failure from detection to recovery
+type Incident struct {
+	Failure explainer.FailureInfo
+	Start   time.Time
+	End     time.Time // zero while still failing
+
+	// Recovery says how the incident ended, when the detector knew
+	Recovery *notifier.Recovery
+}
+
+// Signature identifies the same problem across pods and restarts: the
+// workload (or bare pod), container, reason and exit code
+func Signature(info explainer.FailureInfo) string {
+	signature := fmt.Sprintf("%s container %s %s", workload(info), info.ContainerName, info.Reason)
+	if info.ExitCode != 0 {
+		signature += fmt.Sprintf(" (exit code %d)", info.ExitCode)
+	}
+	return signature
+}
+
+// workload names the workload of a failure, or its pod when it has none
+func workload(info explainer.FailureInfo) string {
+	name := info.Workload
+	if name == "" {
+		name = "Pod/" + info.PodName
+	}
+	name = info.Namespace + "/" + name
+	if info.Cluster != "" {
+		name = "[" + info.Cluster + "] " + name
+	}
+	return name
+}
+
+// History records incidents from failure and recovery events. It implements
+// notifier.Notifier.
+type History struct {
+	mu        sync.Mutex
+	incidents []*Incident
+	firstSeen map[string]time.Time // per signature, kept beyond the retention
+
+	// Retention is how long incidents are kept once they recovered; still
+	// failing ones are always kept (0 keeps everything)
+	Retention time.Duration
+}
+
+func New(retention time.Duration) *History {
+	return &History{firstSeen: make(map[string]time.Time), Retention: retention}
+}
+
+func (h *History) Notify(ctx context.Context, event notifier.Event) error {
+	h.Add(event)
+	return nil
+}
+
+// Add records a failure or recovery event
+func (h *History) Add(event notifier.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch event.Type {
+	case notifier.EventFailure:
+		h.incidents = append(h.incidents, &Incident{Failure: event.Failure, Start: event.Time})
+		signature := Signature(event.Failure)
+		if first, ok := h.firstSeen[signature]; !ok || event.Time.Before(first) {
+			h.firstSeen[signature] = event.Time
+		}
+
+	case notifier.EventRecovery:
+		// Recovery events carry the failure as first reported
+		for _, incident := range slices.Backward(h.incidents) {
+			if incident.End.IsZero() && sameFailure(incident.Failure, event.Failure) {
+				incident.End = event.Time
+				incident.Recovery = event.Recovery
+				break
+			}
+		}
+	}
+
+	if h.Retention > 0 {
+		cutoff := event.Time.Add(-h.Retention)
+		h.incidents = slices.DeleteFunc(h.incidents, func(incident *Incident) bool {
+			return !incident.End.IsZero() && incident.End.Before(cutoff)
+		})
+	}
+}
+
+func sameFailure(a, b explainer.FailureInfo) bool {
+	return a.Cluster == b.Cluster && a.Namespace == b.Namespace && a.PodName == b.PodName &&
+		a.ContainerName == b.ContainerName && a.Reason == b.Reason && a.Workload == b.Workload
+}
+
+// Count is how often a value occurred
+type Count struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Summary aggregates the incidents that started in a window
+type Summary struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	Incidents int `json:"incidents"`
+	Open      int `json:"open"` // still failing at the end of the window
+
+	Namespaces []Count `json:"namespaces"`
+	Workloads  []Count `json:"workloads"`
+	Reasons    []Count `json:"reasons"`
+	ExitCodes  []Count `json:"exitCodes"`
+
+	// Flapping lists pods that failed more than once
+	Flapping []Count `json:"flapping"`
+
+	// Recovered counts the incidents that ended in the window, and
+	// MeanTimeToRecovery averages how long they lasted
+	Recovered          int           `json:"recovered"`
+	MeanTimeToRecovery time.Duration `json:"meanTimeToRecovery"`
+
+	// New signatures were first seen in the window; recurring ones had
+	// failed before it
+	New       []Count `json:"new"`
+	Recurring []Count `json:"recurring"`
+}
+
+// Summarize aggregates the incidents that started from from until to
+func (h *History) Summarize(from, to time.Time) *Summary {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := &Summary{From: from, To: to}
+	var (
+		namespaces = make(map[string]int)
+		workloads  = make(map[string]int)
+		reasons    = make(map[string]int)
+		exitCodes  = make(map[string]int)
+		pods       = make(map[string]int)
+		fresh      = make(map[string]int)
+		recurring  = make(map[string]int)
+		recovery   time.Duration
+	)
+
+	for _, incident := range h.incidents {
+		if !incident.End.IsZero() && !incident.End.Before(from) && incident.End.Before(to) {
+			// Pods deleted while failing never recovered
+			switch {
+			case incident.Recovery == nil:
+				s.Recovered++
+				recovery += incident.End.Sub(incident.Start)
+			case !incident.Recovery.Deleted:
+				s.Recovered++
+				recovery += incident.Recovery.Duration()
+			}
+		}
+
+		if incident.Start.Before(from) || !incident.Start.Before(to) {
+			continue
+		}
+
+		info := incident.Failure
+		s.Incidents++
+		if incident.End.IsZero() || !incident.End.Before(to) {
+			s.Open++
+		}
+
+		namespaces[info.Namespace]++
+		workloads[workload(info)]++
+		reasons[info.Reason]++
+		if info.ExitCode != 0 {
+			exitCodes[fmt.Sprint(info.ExitCode)]++
+		}
+		if info.PodName != "" {
+			pods[info.Namespace+"/"+info.PodName]++
+		}
+
+		signature := Signature(info)
+		if h.firstSeen[signature].Before(from) {
+			recurring[signature]++
+		} else {
+			fresh[signature]++
+		}
+	}
+
+	if s.Recovered > 0 {
+		s.MeanTimeToRecovery = (recovery / time.Duration(s.Recovered)).Round(time.Second)
+	}
+
+	s.Namespaces = counts(namespaces)
+	s.Workloads = counts(workloads)
+	s.Reasons = counts(reasons)
+	s.ExitCodes = counts(exitCodes)
+	s.New = counts(fresh)
+	s.Recurring = counts(recurring)
+	for _, pod := range counts(pods) {
+		if pod.Count > 1 {
+			s.Flapping = append(s.Flapping, pod)
+		}
+	}
+	return s
+}
+
+// counts sorts a tally by count, most first, then by name
+func counts(tally map[string]int) []Count {
+	sorted := make([]Count, 0, len(tally))
+	for _, name := range slices.Sorted(maps.Keys(tally)) {
+		sorted = append(sorted, Count{Name: name, Count: tally[name]})
+	}
+	slices.SortStableFunc(sorted, func(a, b Count) int {
+		return cmp.Compare(b.Count, a.Count)
+	})
+	return sorted
+}
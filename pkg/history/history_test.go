@@ -0,0 +1,157 @@
+package history
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/explainer"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/notifier"
+)
+
+var day = time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+
+// at is a time of the summarized day, e.g. at(9, 30)
+func at(hour, minute int) time.Time {
+	return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+func crash(pod string, exitCode int32) explainer.FailureInfo {
+	return explainer.FailureInfo{
+		Namespace:     "shop",
+		PodName:       pod,
+		ContainerName: "app",
+		Reason:        "CrashLoopBackOff",
+		ExitCode:      exitCode,
+		Workload:      "Deployment/api",
+	}
+}
+
+func failed(info explainer.FailureInfo, t time.Time) notifier.Event {
+	return notifier.Event{Type: notifier.EventFailure, Time: t, Failure: info}
+}
+
+func recovered(info explainer.FailureInfo, start, end time.Time) notifier.Event {
+	recovery := &notifier.Recovery{StartedAt: start, RecoveredAt: end}
+	recovery.DurationSeconds = recovery.Duration().Seconds()
+	return notifier.Event{Type: notifier.EventRecovery, Time: end, Failure: info, Recovery: recovery}
+}
+
+func deleted(info explainer.FailureInfo, start, end time.Time) notifier.Event {
+	event := recovered(info, start, end)
+	event.Recovery.Deleted = true
+	return event
+}
+
+func TestSummarize(t *testing.T) {
+	api, worker := crash("api-1", 1), crash("worker-1", 137)
+	worker.Workload = "Deployment/worker"
+
+	tests := []struct {
+		name   string
+		events []notifier.Event
+		check  func(t *testing.T, s *Summary)
+	}{
+		{
+			name:   "recovered inside the window",
+			events: []notifier.Event{failed(api, at(9, 0)), recovered(api, at(9, 0), at(9, 10))},
+			check: func(t *testing.T, s *Summary) {
+				wantCounts(t, s, 1, 0, 1)
+				wantMTTR(t, s, 10*time.Minute)
+			},
+		},
+		{
+			name:   "started before the window, ended inside it",
+			events: []notifier.Event{failed(api, at(-2, 0)), recovered(api, at(-2, 0), at(1, 0))},
+			check: func(t *testing.T, s *Summary) {
+				// The recovery counts towards MTTR; the incident belongs to the day before
+				wantCounts(t, s, 0, 0, 1)
+				wantMTTR(t, s, 3*time.Hour)
+			},
+		},
+		{
+			name:   "still open",
+			events: []notifier.Event{failed(api, at(23, 0))},
+			check: func(t *testing.T, s *Summary) {
+				wantCounts(t, s, 1, 1, 0)
+				wantMTTR(t, s, 0)
+			},
+		},
+		{
+			name:   "recovered after the window",
+			events: []notifier.Event{failed(api, at(23, 0)), recovered(api, at(23, 0), at(25, 0))},
+			check: func(t *testing.T, s *Summary) {
+				wantCounts(t, s, 1, 1, 0)
+			},
+		},
+		{
+			name: "pod deleted while failing",
+			events: []notifier.Event{
+				failed(api, at(9, 0)), deleted(api, at(9, 0), at(9, 30)),
+				failed(worker, at(10, 0)), recovered(worker, at(10, 0), at(10, 4)),
+			},
+			check: func(t *testing.T, s *Summary) {
+				wantCounts(t, s, 2, 0, 1)
+				wantMTTR(t, s, 4*time.Minute)
+			},
+		},
+		{
+			name: "flapping and exit codes",
+			events: []notifier.Event{
+				failed(api, at(9, 0)), recovered(api, at(9, 0), at(9, 5)),
+				failed(api, at(11, 0)), recovered(api, at(11, 0), at(11, 15)),
+				failed(worker, at(12, 0)),
+			},
+			check: func(t *testing.T, s *Summary) {
+				wantCounts(t, s, 3, 1, 2)
+				wantMTTR(t, s, 10*time.Minute)
+				wantTally(t, "flapping", s.Flapping, []Count{{"shop/api-1", 2}})
+				wantTally(t, "exit codes", s.ExitCodes, []Count{{"1", 2}, {"137", 1}})
+			},
+		},
+		{
+			name: "new and recurring",
+			events: []notifier.Event{
+				failed(api, at(-30, 0)), recovered(api, at(-30, 0), at(-29, 0)),
+				failed(crash("api-2", 1), at(8, 0)),
+				failed(worker, at(9, 0)),
+			},
+			check: func(t *testing.T, s *Summary) {
+				wantTally(t, "recurring", s.Recurring, []Count{{Signature(api), 1}})
+				wantTally(t, "new", s.New, []Count{{Signature(worker), 1}})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New(0)
+			for _, event := range tt.events {
+				h.Add(event)
+			}
+			tt.check(t, h.Summarize(at(0, 0), at(24, 0)))
+		})
+	}
+}
+
+func wantCounts(t *testing.T, s *Summary, incidents, open, recovered int) {
+	t.Helper()
+	if s.Incidents != incidents || s.Open != open || s.Recovered != recovered {
+		t.Errorf("%d incidents, %d open, %d recovered; want %d, %d, %d",
+			s.Incidents, s.Open, s.Recovered, incidents, open, recovered)
+	}
+}
+
+func wantMTTR(t *testing.T, s *Summary, mttr time.Duration) {
+	t.Helper()
+	if s.MeanTimeToRecovery != mttr {
+		t.Errorf("MTTR = %s, want %s", s.MeanTimeToRecovery, mttr)
+	}
+}
+
+func wantTally(t *testing.T, what string, got, want []Count) {
+	t.Helper()
+	if !slices.Equal(got, want) {
+		t.Errorf("%s = %v, want %v", what, got, want)
+	}
+}
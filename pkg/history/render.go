@@ -0,0 +1,70 @@
+package history
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/explainer"
+
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// topN is how many entries each section shows
+const topN = 10
+
+// Write renders the summary for people
+func (s *Summary) Write(w io.Writer) {
+	fmt.Fprintf(w, "📊 FAILURE SUMMARY\n")
+	fmt.Fprintf(w, "=====================================\n")
+	fmt.Fprintf(w, "Window: %s → %s (%s)\n", s.From.Local().Format(time.DateTime), s.To.Local().Format(time.DateTime),
+		duration.HumanDuration(s.To.Sub(s.From)))
+
+	if s.Incidents == 0 && s.Recovered == 0 {
+		fmt.Fprintf(w, "\n✅ No incidents in this window\n\n")
+		return
+	}
+
+	fmt.Fprintf(w, "Incidents: %d (%d still failing)\n", s.Incidents, s.Open)
+	if s.Recovered > 0 {
+		fmt.Fprintf(w, "Mean time to recovery: %s (%d recovered)\n", s.MeanTimeToRecovery, s.Recovered)
+	}
+	fmt.Fprintln(w)
+
+	writeCounts(w, "📁 BY NAMESPACE:", s.Namespaces, nil)
+	writeCounts(w, "📦 BY WORKLOAD:", s.Workloads, nil)
+	writeCounts(w, "🔎 BY REASON:", s.Reasons, nil)
+	writeCounts(w, "🔢 BY EXIT CODE:", s.ExitCodes, func(name string) string {
+		code, _ := strconv.ParseInt(name, 10, 32)
+		_, meaning, _ := strings.Cut(explainer.ExplainExitCode(int32(code)), ": ")
+		return meaning
+	})
+	writeCounts(w, "🔁 TOP FLAPPING PODS:", s.Flapping, nil)
+	writeCounts(w, "🆕 NEW FAILURE SIGNATURES:", s.New, nil)
+	writeCounts(w, "♻️  RECURRING FAILURE SIGNATURES:", s.Recurring, nil)
+}
+
+// writeCounts prints the top entries of a section, with an optional note
+func writeCounts(w io.Writer, title string, counts []Count, note func(name string) string) {
+	if len(counts) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, title)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, count := range counts[:min(len(counts), topN)] {
+		fmt.Fprintf(tw, "  %d\t%s", count.Count, count.Name)
+		if note != nil {
+			fmt.Fprintf(tw, "\t%s", note(count.Name))
+		}
+		fmt.Fprintln(tw)
+	}
+	tw.Flush()
+	if len(counts) > topN {
+		fmt.Fprintf(w, "  … and %d more\n", len(counts)-topN)
+	}
+	fmt.Fprintln(w)
+}
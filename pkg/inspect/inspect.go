@@ -0,0 +1,324 @@
+// Package inspect runs every check the detector knows about against a single
+// pod, healthy or not, and renders the result as one report
+package inspect
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/detector"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/explainer"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Report is everything known about one pod
+type Report struct {
+	Pod            *corev1.Pod
+	Workload       string // e.g. "Deployment/web"; empty for bare pods
+	WorkloadStatus string // e.g. "2/3 replicas ready"
+	Node           *corev1.Node
+	Events         []corev1.Event
+	Logs           []ContainerLog
+	References     []Reference
+	Failures       []explainer.FailureInfo
+
+	// Problems lists checks that could not run, e.g. for lack of permissions
+	Problems []string
+}
+
+// ContainerLog is the tail of one container instance's log
+type ContainerLog struct {
+	Container string
+	Previous  bool // the instance before the last restart
+	Log       string
+	Err       error
+}
+
+// Reference is a ConfigMap or Secret the pod depends on
+type Reference struct {
+	Kind     string // ConfigMap or Secret
+	Name     string
+	Key      string // empty when the whole object is used
+	Optional bool
+	Via      string // e.g. "env DB_URL in app"
+	Found    bool
+	Err      error // set when the lookup failed for another reason than not found
+}
+
+// Options tunes what is collected
+type Options struct {
+	// LogLines is how many lines of each log to keep (default 20)
+	LogLines int64
+}
+
+// Pod collects the report for a pod. Only failing to get the pod itself is an
+// error; other failed checks are listed in Report.Problems.
+func Pod(ctx context.Context, clientset kubernetes.Interface, namespace, name string, opts Options) (*Report, error) {
+	if opts.LogLines == 0 {
+		opts.LogLines = 20
+	}
+
+	pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod: %w", err)
+	}
+
+	report := &Report{Pod: pod}
+	podDetector := detector.New(clientset, detector.Options{})
+
+	report.Workload = podDetector.Workload(ctx, pod)
+	report.WorkloadStatus = report.workloadStatus(ctx, clientset)
+	report.Failures = podDetector.Failures(ctx, pod)
+
+	if pod.Spec.NodeName != "" {
+		node, err := clientset.CoreV1().Nodes().Get(ctx, pod.Spec.NodeName, metav1.GetOptions{})
+		if err != nil {
+			report.problem("get node "+pod.Spec.NodeName, err)
+		} else {
+			report.Node = node
+		}
+	}
+
+	report.collectEvents(ctx, clientset)
+	report.collectLogs(ctx, clientset, opts.LogLines)
+	report.collectReferences(ctx, clientset)
+
+	return report, nil
+}
+
+func (r *Report) problem(what string, err error) {
+	r.Problems = append(r.Problems, fmt.Sprintf("could not %s: %v", what, err))
+}
+
+// workloadStatus summarizes the rollout state of the pod's workload
+func (r *Report) workloadStatus(ctx context.Context, clientset kubernetes.Interface) string {
+	kind, name, ok := strings.Cut(r.Workload, "/")
+	if !ok {
+		return ""
+	}
+	namespace := r.Pod.Namespace
+
+	switch kind {
+	case "Deployment":
+		deployment, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			r.problem("get "+r.Workload, err)
+			return ""
+		}
+		desired := int32(1)
+		if deployment.Spec.Replicas != nil {
+			desired = *deployment.Spec.Replicas
+		}
+		return fmt.Sprintf("%d/%d replicas ready, %d up to date", deployment.Status.ReadyReplicas, desired, deployment.Status.UpdatedReplicas)
+	case "StatefulSet":
+		statefulSet, err := clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			r.problem("get "+r.Workload, err)
+			return ""
+		}
+		desired := int32(1)
+		if statefulSet.Spec.Replicas != nil {
+			desired = *statefulSet.Spec.Replicas
+		}
+		return fmt.Sprintf("%d/%d replicas ready", statefulSet.Status.ReadyReplicas, desired)
+	case "DaemonSet":
+		daemonSet, err := clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			r.problem("get "+r.Workload, err)
+			return ""
+		}
+		return fmt.Sprintf("%d/%d pods ready", daemonSet.Status.NumberReady, daemonSet.Status.DesiredNumberScheduled)
+	case "Job":
+		job, err := clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			r.problem("get "+r.Workload, err)
+			return ""
+		}
+		return fmt.Sprintf("%d active, %d succeeded, %d failed", job.Status.Active, job.Status.Succeeded, job.Status.Failed)
+	}
+	return ""
+}
+
+func (r *Report) collectEvents(ctx context.Context, clientset kubernetes.Interface) {
+	selector := fields.Set{
+		"involvedObject.kind": "Pod",
+		"involvedObject.name": r.Pod.Name,
+	}.AsSelector().String()
+
+	events, err := clientset.CoreV1().Events(r.Pod.Namespace).List(ctx, metav1.ListOptions{FieldSelector: selector})
+	if err != nil {
+		r.problem("list events", err)
+		return
+	}
+
+	for _, event := range events.Items {
+		// Skip events for an earlier pod with the same name
+		if event.InvolvedObject.UID == "" || event.InvolvedObject.UID == r.Pod.UID {
+			r.Events = append(r.Events, event)
+		}
+	}
+	slices.SortStableFunc(r.Events, func(a, b corev1.Event) int {
+		return detector.EventTime(a).Compare(detector.EventTime(b))
+	})
+}
+
+func (r *Report) collectLogs(ctx context.Context, clientset kubernetes.Interface, lines int64) {
+	statuses := append(slices.Clone(r.Pod.Status.InitContainerStatuses), r.Pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		// Containers that never started have nothing to show
+		if status.State.Waiting != nil && status.LastTerminationState.Terminated == nil {
+			continue
+		}
+
+		if status.State.Running != nil || status.State.Terminated != nil {
+			r.Logs = append(r.Logs, fetchLog(ctx, clientset, r.Pod, status.Name, false, lines))
+		}
+		if status.RestartCount > 0 || status.LastTerminationState.Terminated != nil {
+			r.Logs = append(r.Logs, fetchLog(ctx, clientset, r.Pod, status.Name, true, lines))
+		}
+	}
+}
+
+func fetchLog(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod, container string, previous bool, lines int64) ContainerLog {
+	logs, err := clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: container,
+		Previous:  previous,
+		TailLines: &lines,
+	}).Do(ctx).Raw()
+
+	return ContainerLog{Container: container, Previous: previous, Log: string(logs), Err: err}
+}
+
+// collectReferences finds every ConfigMap and Secret the pod spec names and
+// checks they and the keys used exist. Secret values are never read out.
+func (r *Report) collectReferences(ctx context.Context, clientset kubernetes.Interface) {
+	spec := r.Pod.Spec
+	add := func(ref Reference) {
+		for _, existing := range r.References {
+			if existing.Kind == ref.Kind && existing.Name == ref.Name && existing.Key == ref.Key {
+				return
+			}
+		}
+		r.References = append(r.References, ref)
+	}
+
+	for _, volume := range spec.Volumes {
+		via := "volume " + volume.Name
+		switch {
+		case volume.ConfigMap != nil:
+			addItems(add, "ConfigMap", volume.ConfigMap.Name, volume.ConfigMap.Items, optional(volume.ConfigMap.Optional), via)
+		case volume.Secret != nil:
+			addItems(add, "Secret", volume.Secret.SecretName, volume.Secret.Items, optional(volume.Secret.Optional), via)
+		case volume.Projected != nil:
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					addItems(add, "ConfigMap", source.ConfigMap.Name, source.ConfigMap.Items, optional(source.ConfigMap.Optional), via)
+				}
+				if source.Secret != nil {
+					addItems(add, "Secret", source.Secret.Name, source.Secret.Items, optional(source.Secret.Optional), via)
+				}
+			}
+		}
+	}
+
+	containers := append(slices.Clone(spec.InitContainers), spec.Containers...)
+	for _, container := range containers {
+		for _, envFrom := range container.EnvFrom {
+			via := "envFrom in " + container.Name
+			if envFrom.ConfigMapRef != nil {
+				add(Reference{Kind: "ConfigMap", Name: envFrom.ConfigMapRef.Name, Optional: optional(envFrom.ConfigMapRef.Optional), Via: via})
+			}
+			if envFrom.SecretRef != nil {
+				add(Reference{Kind: "Secret", Name: envFrom.SecretRef.Name, Optional: optional(envFrom.SecretRef.Optional), Via: via})
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			via := fmt.Sprintf("env %s in %s", env.Name, container.Name)
+			if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil {
+				add(Reference{Kind: "ConfigMap", Name: ref.Name, Key: ref.Key, Optional: optional(ref.Optional), Via: via})
+			}
+			if ref := env.ValueFrom.SecretKeyRef; ref != nil {
+				add(Reference{Kind: "Secret", Name: ref.Name, Key: ref.Key, Optional: optional(ref.Optional), Via: via})
+			}
+		}
+	}
+
+	for _, pullSecret := range spec.ImagePullSecrets {
+		add(Reference{Kind: "Secret", Name: pullSecret.Name, Via: "imagePullSecrets"})
+	}
+
+	// Look each object up once, however many keys are used
+	type lookupResult struct {
+		keys map[string]bool
+		err  error
+	}
+	cache := make(map[string]lookupResult)
+	for i := range r.References {
+		ref := &r.References[i]
+		id := ref.Kind + "/" + ref.Name
+		result, ok := cache[id]
+		if !ok {
+			result.keys, result.err = lookup(ctx, clientset, r.Pod.Namespace, ref.Kind, ref.Name)
+			cache[id] = result
+		}
+
+		switch {
+		case apierrors.IsNotFound(result.err):
+		case result.err != nil:
+			ref.Err = result.err
+		default:
+			ref.Found = ref.Key == "" || result.keys[ref.Key]
+		}
+	}
+}
+
+func addItems(add func(Reference), kind, name string, items []corev1.KeyToPath, optional bool, via string) {
+	if len(items) == 0 {
+		add(Reference{Kind: kind, Name: name, Optional: optional, Via: via})
+		return
+	}
+	for _, item := range items {
+		add(Reference{Kind: kind, Name: name, Key: item.Key, Optional: optional, Via: via})
+	}
+}
+
+func optional(flag *bool) bool {
+	return flag != nil && *flag
+}
+
+// lookup returns the keys of a ConfigMap or Secret
+func lookup(ctx context.Context, clientset kubernetes.Interface, namespace, kind, name string) (map[string]bool, error) {
+	keys := make(map[string]bool)
+	if kind == "ConfigMap" {
+		configMap, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for key := range configMap.Data {
+			keys[key] = true
+		}
+		for key := range configMap.BinaryData {
+			keys[key] = true
+		}
+		return keys, nil
+	}
+
+	secret, err := clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for key := range secret.Data {
+		keys[key] = true
+	}
+	return keys, nil
+}
@@ -0,0 +1,302 @@
+package inspect
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/detector"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/explainer"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// Write renders the report as text
+func (r *Report) Write(w io.Writer) {
+	pod := r.Pod
+
+	fmt.Fprintf(w, "🩺 POD DIAGNOSIS\n")
+	fmt.Fprintf(w, "=====================================\n")
+	fmt.Fprintf(w, "Pod:       %s/%s\n", pod.Namespace, pod.Name)
+	phase := string(pod.Status.Phase)
+	if pod.Status.Reason != "" {
+		phase += " (" + pod.Status.Reason + ")"
+	}
+	fmt.Fprintf(w, "Phase:     %s\n", phase)
+	if r.Workload != "" {
+		workload := r.Workload
+		if r.WorkloadStatus != "" {
+			workload += " (" + r.WorkloadStatus + ")"
+		}
+		fmt.Fprintf(w, "Workload:  %s\n", workload)
+	}
+	if pod.Spec.NodeName != "" {
+		fmt.Fprintf(w, "Node:      %s\n", pod.Spec.NodeName)
+	}
+	if pod.Status.StartTime != nil {
+		fmt.Fprintf(w, "Started:   %s ago\n", age(pod.Status.StartTime.Time))
+	}
+	if pod.Status.QOSClass != "" {
+		fmt.Fprintf(w, "QoS:       %s\n", pod.Status.QOSClass)
+	}
+	if pod.Status.PodIP != "" {
+		fmt.Fprintf(w, "IP:        %s\n", pod.Status.PodIP)
+	}
+
+	r.writeContainers(w)
+	r.writeConditions(w)
+	r.writeNode(w)
+	r.writeEvents(w)
+	r.writeReferences(w)
+	r.writeLogs(w)
+
+	fmt.Fprintf(w, "\n🚨 DIAGNOSIS:\n")
+	if len(r.Failures) == 0 {
+		fmt.Fprintf(w, "✅ No failing containers found\n")
+	}
+	for _, info := range r.Failures {
+		fmt.Fprintf(w, "\n%s\n", explainer.Explain(info))
+	}
+
+	if len(r.Problems) > 0 {
+		fmt.Fprintf(w, "\n⚠️  INCOMPLETE CHECKS:\n")
+		for _, problem := range r.Problems {
+			fmt.Fprintf(w, "  - %s\n", problem)
+		}
+	}
+}
+
+func (r *Report) writeContainers(w io.Writer) {
+	fmt.Fprintf(w, "\n📦 CONTAINERS:\n")
+
+	statuses := make(map[string]corev1.ContainerStatus)
+	for _, status := range append(slices.Clone(r.Pod.Status.InitContainerStatuses), r.Pod.Status.ContainerStatuses...) {
+		statuses[status.Name] = status
+	}
+
+	write := func(container corev1.Container, init bool) {
+		kind := ""
+		if init {
+			kind = " [init]"
+		}
+		fmt.Fprintf(w, "%s (%s)%s\n", container.Name, container.Image, kind)
+
+		if status, ok := statuses[container.Name]; ok {
+			fmt.Fprintf(w, "  State:       %s\n", describeState(status.State))
+			if status.LastTerminationState.Terminated != nil {
+				fmt.Fprintf(w, "  Last state:  %s\n", describeState(status.LastTerminationState))
+			}
+			fmt.Fprintf(w, "  Ready:       %t, %d restarts\n", status.Ready, status.RestartCount)
+		} else {
+			fmt.Fprintf(w, "  State:       not created yet\n")
+		}
+
+		if requests := describeResources(container.Resources.Requests); requests != "" {
+			fmt.Fprintf(w, "  Requests:    %s\n", requests)
+		}
+		if limits := describeResources(container.Resources.Limits); limits != "" {
+			fmt.Fprintf(w, "  Limits:      %s\n", limits)
+		}
+		if container.StartupProbe != nil {
+			fmt.Fprintf(w, "  Startup:     %s\n", describeProbe(container.StartupProbe))
+		}
+		if container.LivenessProbe != nil {
+			fmt.Fprintf(w, "  Liveness:    %s\n", describeProbe(container.LivenessProbe))
+		}
+		if container.ReadinessProbe != nil {
+			fmt.Fprintf(w, "  Readiness:   %s\n", describeProbe(container.ReadinessProbe))
+		}
+	}
+
+	for _, container := range r.Pod.Spec.InitContainers {
+		write(container, true)
+	}
+	for _, container := range r.Pod.Spec.Containers {
+		write(container, false)
+	}
+}
+
+func (r *Report) writeConditions(w io.Writer) {
+	if len(r.Pod.Status.Conditions) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n📋 CONDITIONS:\n")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  TYPE\tSTATUS\tREASON\tMESSAGE")
+	for _, condition := range r.Pod.Status.Conditions {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", condition.Type, condition.Status, orDash(condition.Reason), orDash(condition.Message))
+	}
+	tw.Flush()
+}
+
+func (r *Report) writeNode(w io.Writer) {
+	if r.Node == nil {
+		if r.Pod.Spec.NodeName == "" {
+			fmt.Fprintf(w, "\n🖥️  NODE:\nNot scheduled yet, see the PodScheduled condition and events\n")
+		}
+		return
+	}
+
+	fmt.Fprintf(w, "\n🖥️  NODE: %s\n", r.Node.Name)
+	var problems []string
+	for _, condition := range r.Node.Status.Conditions {
+		switch {
+		case condition.Type == corev1.NodeReady && condition.Status != corev1.ConditionTrue:
+			problems = append(problems, fmt.Sprintf("NotReady (%s)", orDash(condition.Message)))
+		case condition.Type != corev1.NodeReady && condition.Status == corev1.ConditionTrue:
+			problems = append(problems, fmt.Sprintf("%s (%s)", condition.Type, orDash(condition.Message)))
+		}
+	}
+	if r.Node.Spec.Unschedulable {
+		problems = append(problems, "cordoned")
+	}
+
+	if len(problems) == 0 {
+		fmt.Fprintf(w, "✅ Ready, no pressure conditions\n")
+	}
+	for _, problem := range problems {
+		fmt.Fprintf(w, "❌ %s\n", problem)
+	}
+}
+
+func (r *Report) writeEvents(w io.Writer) {
+	fmt.Fprintf(w, "\n📅 EVENTS:\n")
+	if len(r.Events) == 0 {
+		fmt.Fprintf(w, "No events (they expire after an hour by default)\n")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  AGE\tTYPE\tREASON\tCOUNT\tMESSAGE")
+	for _, event := range r.Events {
+		count := event.Count
+		if event.Series != nil {
+			count = event.Series.Count
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%d\t%s\n", age(detector.EventTime(event)), event.Type, event.Reason, max(count, 1),
+			strings.TrimSpace(event.Message))
+	}
+	tw.Flush()
+}
+
+func (r *Report) writeReferences(w io.Writer) {
+	if len(r.References) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n🔑 CONFIGMAPS AND SECRETS:\n")
+	for _, ref := range r.References {
+		name := ref.Kind + " " + ref.Name
+		if ref.Key != "" {
+			name += " key " + ref.Key
+		}
+
+		switch {
+		case ref.Err != nil:
+			fmt.Fprintf(w, "⚠️  %s (%s): %v\n", name, ref.Via, ref.Err)
+		case ref.Found:
+			fmt.Fprintf(w, "✅ %s (%s)\n", name, ref.Via)
+		case ref.Optional:
+			fmt.Fprintf(w, "➖ %s (%s): missing, but optional\n", name, ref.Via)
+		default:
+			fmt.Fprintf(w, "❌ %s (%s): missing\n", name, ref.Via)
+		}
+	}
+}
+
+func (r *Report) writeLogs(w io.Writer) {
+	if len(r.Logs) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n📜 LOGS:\n")
+	for _, log := range r.Logs {
+		instance := "current"
+		if log.Previous {
+			instance = "previous"
+		}
+		fmt.Fprintf(w, "--- %s (%s) ---\n", log.Container, instance)
+
+		switch {
+		case log.Err != nil:
+			fmt.Fprintf(w, "(unavailable: %v)\n", log.Err)
+		case strings.TrimSpace(log.Log) == "":
+			fmt.Fprintf(w, "(empty)\n")
+		default:
+			fmt.Fprintf(w, "%s\n", strings.TrimRight(log.Log, "\n"))
+		}
+	}
+}
+
+func describeState(state corev1.ContainerState) string {
+	switch {
+	case state.Waiting != nil:
+		return withMessage("Waiting: "+state.Waiting.Reason, state.Waiting.Message)
+	case state.Running != nil:
+		return fmt.Sprintf("Running for %s", age(state.Running.StartedAt.Time))
+	case state.Terminated != nil:
+		terminated := state.Terminated
+		text := fmt.Sprintf("Terminated: %s (exit code %d)", orDash(terminated.Reason), terminated.ExitCode)
+		if !terminated.FinishedAt.IsZero() {
+			text += fmt.Sprintf(" %s ago", age(terminated.FinishedAt.Time))
+		}
+		return withMessage(text, terminated.Message)
+	}
+	return "unknown"
+}
+
+func withMessage(text, message string) string {
+	if message = strings.TrimSpace(message); message != "" {
+		return text + " - " + message
+	}
+	return text
+}
+
+func describeResources(resources corev1.ResourceList) string {
+	var parts []string
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourceEphemeralStorage} {
+		if quantity, ok := resources[name]; ok {
+			parts = append(parts, fmt.Sprintf("%s=%s", name, quantity.String()))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// describeProbe formats a probe like kubectl describe does
+func describeProbe(probe *corev1.Probe) string {
+	var target string
+	switch {
+	case probe.HTTPGet != nil:
+		target = fmt.Sprintf("http-get %s://:%s%s", strings.ToLower(string(probe.HTTPGet.Scheme)), probe.HTTPGet.Port.String(), probe.HTTPGet.Path)
+	case probe.TCPSocket != nil:
+		target = fmt.Sprintf("tcp-socket :%s", probe.TCPSocket.Port.String())
+	case probe.GRPC != nil:
+		target = fmt.Sprintf("grpc :%d", probe.GRPC.Port)
+	case probe.Exec != nil:
+		target = fmt.Sprintf("exec [%s]", strings.Join(probe.Exec.Command, " "))
+	default:
+		target = "unknown"
+	}
+
+	return fmt.Sprintf("%s delay=%ds timeout=%ds period=%ds #success=%d #failure=%d", target,
+		probe.InitialDelaySeconds, probe.TimeoutSeconds, probe.PeriodSeconds, probe.SuccessThreshold, probe.FailureThreshold)
+}
+
+func age(t time.Time) string {
+	if t.IsZero() {
+		return "?"
+	}
+	return duration.HumanDuration(time.Since(t))
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
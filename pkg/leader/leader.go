@@ -0,0 +1,166 @@
+package leader
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/metrics"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// Config configures Lease-based leader election
+type Config struct {
+	Namespace     string // namespace of the Lease; see DefaultNamespace
+	LeaseName     string
+	Identity      string // unique per replica, defaults to the hostname (pod name)
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+}
+
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// DefaultNamespace is the namespace the detector runs in when in-cluster,
+// otherwise "default"
+func DefaultNamespace() string {
+	if ns := os.Getenv("POD_NAMESPACE"); ns != "" {
+		return ns
+	}
+	if data, err := os.ReadFile(serviceAccountNamespaceFile); err == nil {
+		return strings.TrimSpace(string(data))
+	}
+	return "default"
+}
+
+// Run campaigns for leadership until ctx is cancelled, calling onChange
+// whenever this replica gains or loses it. Losing the lease doesn't stop the
+// detector: it becomes a follower and campaigns again.
+//
+// On gaining it, handover is when the previous leader last renewed or released
+// the Lease: it reported everything it found before then. It is zero when no
+// other replica held the Lease.
+func Run(ctx context.Context, clientset kubernetes.Interface, config Config, onChange func(leading bool, handover time.Time)) error {
+	if config.Identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return err
+		}
+		config.Identity = hostname
+	}
+
+	lock := &observingLock{Interface: &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      config.LeaseName,
+			Namespace: config.Namespace,
+		},
+		Client: clientset.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: config.Identity,
+		},
+	}}
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   config.LeaseDuration,
+		RenewDeadline:   config.RenewDeadline,
+		RetryPeriod:     config.RetryPeriod,
+		ReleaseOnCancel: true,
+		Name:            config.LeaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(context.Context) {
+				slog.Info("Became leader, reporting failures", "lease", config.Namespace+"/"+config.LeaseName, "identity", config.Identity)
+				metrics.IsLeader.Set(1)
+				metrics.LeaderTransitions.Inc()
+				onChange(true, lock.handover())
+			},
+			OnStoppedLeading: func() {
+				slog.Info("Lost leadership, following", "lease", config.Namespace+"/"+config.LeaseName, "identity", config.Identity)
+				metrics.IsLeader.Set(0)
+				lock.stepDown()
+				onChange(false, time.Time{})
+			},
+			OnNewLeader: func(identity string) {
+				if identity != config.Identity {
+					slog.Info("Following leader", "leader", identity)
+				}
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for ctx.Err() == nil {
+		elector.Run(ctx)
+	}
+	return nil
+}
+
+// observingLock remembers the Lease record this replica replaced when it took
+// over, whether another replica or an earlier run under the same identity
+// wrote it
+type observingLock struct {
+	resourcelock.Interface
+
+	mu       sync.Mutex
+	observed *resourcelock.LeaderElectionRecord // as last read
+	holding  bool
+	previous *resourcelock.LeaderElectionRecord
+}
+
+func (l *observingLock) Get(ctx context.Context) (*resourcelock.LeaderElectionRecord, []byte, error) {
+	record, raw, err := l.Interface.Get(ctx)
+
+	l.mu.Lock()
+	l.observed = record
+	l.mu.Unlock()
+
+	return record, raw, err
+}
+
+func (l *observingLock) Create(ctx context.Context, record resourcelock.LeaderElectionRecord) error {
+	return l.took(l.Interface.Create(ctx, record))
+}
+
+func (l *observingLock) Update(ctx context.Context, record resourcelock.LeaderElectionRecord) error {
+	return l.took(l.Interface.Update(ctx, record))
+}
+
+// took notes the record replaced by a write that acquired the Lease; later
+// writes renew or release it
+func (l *observingLock) took(err error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err == nil && !l.holding {
+		l.holding = true
+		l.previous = l.observed
+	}
+	return err
+}
+
+// stepDown is called once the Lease is lost or released
+func (l *observingLock) stepDown() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.holding = false
+}
+
+// handover is when the replaced holder last renewed the Lease, or released it
+func (l *observingLock) handover() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.previous == nil {
+		return time.Time{}
+	}
+	return l.previous.RenewTime.Time
+}
@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+
+	"k8s.io/klog/v2"
+)
+
+// Setup installs the default slog logger. Operational logs go to w (stderr in
+// practice) so they never mix with the explanations printed on stdout.
+//
+// Verbosity 0 logs info and above, 1 adds debug messages and every further
+// level lowers the threshold by another step, like klog's -v.
+func Setup(w io.Writer, verbosity int, format string) error {
+	opts := &slog.HandlerOptions{Level: Level(verbosity)}
+
+	var handler slog.Handler
+	switch format {
+	case "text", "":
+		handler = slog.NewTextHandler(w, opts)
+	case "json":
+		handler = slog.NewJSONHandler(w, opts)
+	default:
+		return fmt.Errorf("unknown log format %q, expected text or json", format)
+	}
+
+	logger := slog.New(handler)
+	slog.SetDefault(logger)
+
+	// client-go logs through klog; send it through the same handler
+	klog.SetSlogLogger(logger)
+
+	return nil
+}
+
+// Level maps a -v verbosity to a slog level
+func Level(verbosity int) slog.Level {
+	return slog.LevelInfo - slog.Level(4*verbosity)
+}
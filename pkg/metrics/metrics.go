@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds every detector metric plus the Go runtime and process collectors
+var Registry = prometheus.NewRegistry()
+
+// The per-pod metrics have a cluster label, empty unless several clusters
+// are watched, so series of pods with the same name don't collide
+var (
+	// FailuresTotal counts newly detected failures
+	FailuresTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "pod_detective_failures_total",
+		Help: "Number of pod failures detected.",
+	}, []string{"cluster", "namespace", "workload", "reason", "container"})
+
+	// ActiveIncidents is the number of failures not yet recovered
+	ActiveIncidents = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "pod_detective_active_incidents",
+		Help: "Number of detected failures that have not recovered yet.",
+	})
+
+	// LastExitCode is the exit code of the last failed run of a container
+	LastExitCode = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "pod_detective_last_exit_code",
+		Help: "Exit code of the last failed run of a container with an active incident.",
+	}, []string{"cluster", "namespace", "pod", "container"})
+
+	// TimeToDetect measures the delay between a failure happening and the detector reporting it
+	TimeToDetect = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "pod_detective_time_to_detect_seconds",
+		Help:    "Time between a container failing and the detector reporting it.",
+		Buckets: []float64{1, 5, 10, 15, 30, 60, 120, 300, 600, 1800},
+	})
+
+	// TimeToRecover measures how long incidents last, from the failure until
+	// the pod or its replacement is healthy
+	TimeToRecover = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "pod_detective_time_to_recover_seconds",
+		Help:    "Time between a container failing and its pod, or a replacement, being healthy again.",
+		Buckets: []float64{10, 30, 60, 120, 300, 600, 1800, 3600, 7200, 21600},
+	})
+
+	// ListErrors counts failed pod list calls
+	ListErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "pod_detective_list_errors_total",
+		Help: "Number of failed pod list/watch calls.",
+	})
+
+	// LogFetchDuration measures how long fetching container logs takes
+	LogFetchDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "pod_detective_log_fetch_duration_seconds",
+		Help:    "Latency of container log fetches.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	// IsLeader is 1 while this replica holds the leader lease
+	IsLeader = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "pod_detective_is_leader",
+		Help: "Whether this replica is the leader (1) or a follower (0).",
+	})
+
+	// LeaderTransitions counts how often this replica became leader
+	LeaderTransitions = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "pod_detective_leader_transitions_total",
+		Help: "Number of times this replica acquired leadership.",
+	})
+
+	// NotifierFailures counts events a notifier failed to deliver
+	NotifierFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "pod_detective_notifier_failures_total",
+		Help: "Number of events a notifier failed to deliver.",
+	}, []string{"notifier"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		FailuresTotal,
+		ActiveIncidents,
+		LastExitCode,
+		TimeToDetect,
+		TimeToRecover,
+		ListErrors,
+		LogFetchDuration,
+		NotifierFailures,
+		IsLeader,
+		LeaderTransitions,
+	)
+}
+
+// Handler serves the registry in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
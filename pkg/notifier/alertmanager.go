@@ -0,0 +1,230 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/explainer"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/metrics"
+)
+
+// AlertName is the alertname label of every alert the detector raises
+const AlertName = "PodFailureDetected"
+
+// AlertmanagerConfig configures the Alertmanager v2 API sink
+type AlertmanagerConfig struct {
+	URL             string // base URL, e.g. http://alertmanager:9093
+	RefreshInterval time.Duration
+	RunbookURL      string // optional, added as the runbook_url annotation
+	GeneratorURL    string // optional link back to the detector
+	Labels          map[string]string
+	Timeout         time.Duration
+}
+
+// Alertmanager keeps one alert per active incident firing in Alertmanager.
+// Active alerts are re-posted every RefreshInterval with a fresh endsAt, and
+// resolved (endsAt set to now) when the pod recovers.
+type Alertmanager struct {
+	config AlertmanagerConfig
+	client *http.Client
+
+	mu     sync.Mutex
+	active map[string]postableAlert
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// postableAlert mirrors the Alertmanager v2 API schema
+type postableAlert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	StartsAt     time.Time         `json:"startsAt,omitempty"`
+	EndsAt       time.Time         `json:"endsAt,omitempty"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+func NewAlertmanager(config AlertmanagerConfig) (*Alertmanager, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("alertmanager URL is required")
+	}
+	if config.RefreshInterval == 0 {
+		config.RefreshInterval = time.Minute
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 10 * time.Second
+	}
+
+	am := &Alertmanager{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+		active: make(map[string]postableAlert),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go am.refreshLoop()
+
+	return am, nil
+}
+
+// Notify fires an alert for a failure and resolves it on recovery
+func (am *Alertmanager) Notify(ctx context.Context, event Event) error {
+	key := alertKey(event.Failure)
+
+	am.mu.Lock()
+	var alert postableAlert
+	switch event.Type {
+	case EventFailure:
+		alert = am.newAlert(event)
+		alert.EndsAt = am.expiry()
+		am.active[key] = alert
+	case EventRecovery:
+		existing, ok := am.active[key]
+		if !ok {
+			am.mu.Unlock()
+			return nil
+		}
+		delete(am.active, key)
+		alert = existing
+		alert.EndsAt = event.Time
+		if event.Recovery != nil {
+			alert.Annotations = maps.Clone(alert.Annotations)
+			alert.Annotations["recovery"] = event.Recovery.String()
+		}
+	}
+	am.mu.Unlock()
+
+	return am.post(ctx, []postableAlert{alert})
+}
+
+// Close stops refreshing active alerts. They expire on their own in Alertmanager.
+func (am *Alertmanager) Close() error {
+	close(am.stop)
+	<-am.done
+	return nil
+}
+
+func (am *Alertmanager) refreshLoop() {
+	defer close(am.done)
+
+	ticker := time.NewTicker(am.config.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-am.stop:
+			return
+		case <-ticker.C:
+		}
+
+		am.mu.Lock()
+		alerts := make([]postableAlert, 0, len(am.active))
+		for key, alert := range am.active {
+			alert.EndsAt = am.expiry()
+			am.active[key] = alert
+			alerts = append(alerts, alert)
+		}
+		am.mu.Unlock()
+
+		if len(alerts) == 0 {
+			continue
+		}
+		if err := am.post(context.Background(), alerts); err != nil {
+			metrics.NotifierFailures.WithLabelValues(Name(am)).Inc()
+			slog.Warn("Failed to refresh Alertmanager alerts", "alerts", len(alerts), "error", err)
+		}
+	}
+}
+
+// expiry is how long an alert stays firing if the detector stops refreshing it
+func (am *Alertmanager) expiry() time.Time {
+	return time.Now().UTC().Add(3 * am.config.RefreshInterval)
+}
+
+func (am *Alertmanager) newAlert(event Event) postableAlert {
+	info := event.Failure
+
+	labels := map[string]string{
+		"alertname": AlertName,
+		"namespace": info.Namespace,
+		"pod":       info.PodName,
+		"container": info.ContainerName,
+		"reason":    info.Reason,
+	}
+	if info.Workload != "" {
+		labels["workload"] = info.Workload
+	}
+	if info.Cluster != "" {
+		labels["cluster"] = info.Cluster
+	}
+	for name, value := range am.config.Labels {
+		labels[name] = value
+	}
+
+	annotations := map[string]string{
+		"summary":     explainer.Summary(info),
+		"explanation": event.Diagnosis,
+		"runbook":     runbook(info),
+	}
+	if am.config.RunbookURL != "" {
+		annotations["runbook_url"] = am.config.RunbookURL
+	}
+
+	return postableAlert{
+		Labels:       labels,
+		Annotations:  annotations,
+		StartsAt:     event.Time,
+		GeneratorURL: am.config.GeneratorURL,
+	}
+}
+
+func (am *Alertmanager) post(ctx context.Context, alerts []postableAlert) error {
+	body, err := json.Marshal(alerts)
+	if err != nil {
+		return fmt.Errorf("failed to encode alerts: %w", err)
+	}
+
+	url := strings.TrimSuffix(am.config.URL, "/") + "/api/v2/alerts"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := am.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("alertmanager returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+func alertKey(info explainer.FailureInfo) string {
+	return fmt.Sprintf("%s/%s/%s/%s/%s/%s", info.Cluster, info.Namespace, info.Workload, info.PodName, info.ContainerName, info.Reason)
+}
+
+// runbook renders the debug commands as a plain-text annotation
+func runbook(info explainer.FailureInfo) string {
+	var b strings.Builder
+	for _, cmd := range explainer.DebugCommands(info) {
+		if cmd.Description != "" {
+			b.WriteString("# " + cmd.Description + "\n")
+		}
+		b.WriteString(cmd.Command + "\n")
+	}
+	return b.String()
+}
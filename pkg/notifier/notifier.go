@@ -0,0 +1,208 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/explainer"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/metrics"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// EventType distinguishes a newly detected failure from a recovery
+type EventType string
+
+const (
+	EventFailure  EventType = "failure"
+	EventRecovery EventType = "recovery"
+)
+
+// Event is a single failure or recovery reported by the detector
+type Event struct {
+	Type      EventType
+	Time      time.Time
+	Failure   explainer.FailureInfo
+	Diagnosis string
+
+	// Recovery says how the incident ended (recoveries only)
+	Recovery *Recovery
+}
+
+// Recovery describes how an incident ended
+type Recovery struct {
+	StartedAt       time.Time `json:"startedAt"` // when the failure began, as far as the pod status tells
+	RecoveredAt     time.Time `json:"recoveredAt"`
+	DurationSeconds float64   `json:"durationSeconds"`
+
+	// Restarts of the failing container, as last seen
+	Restarts int32 `json:"restarts"`
+
+	// ReplacedBy is the healthy pod that took over when the failing pod was
+	// replaced rather than recovering itself
+	ReplacedBy string `json:"replacedBy,omitempty"`
+
+	// Deleted means the pod went away and nothing healthy replaced it
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// Duration is how long the incident lasted
+func (r *Recovery) Duration() time.Duration {
+	return r.RecoveredAt.Sub(r.StartedAt).Round(time.Second)
+}
+
+// String describes the recovery, e.g. "recovered after 4m12s (3 restarts)"
+func (r *Recovery) String() string {
+	what := "recovered"
+	if r.Deleted {
+		what = "pod deleted"
+	}
+
+	restarts := fmt.Sprintf("%d restarts", r.Restarts)
+	if r.Restarts == 1 {
+		restarts = "1 restart"
+	}
+
+	text := fmt.Sprintf("%s after %s (%s)", what, r.Duration(), restarts)
+	if r.ReplacedBy != "" {
+		text += ", replaced by pod " + r.ReplacedBy
+	}
+	return text
+}
+
+// Notifier delivers detector events to an external system
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+// Name returns a short name for a notifier, e.g. "webhook"
+func Name(n Notifier) string {
+	t := reflect.TypeOf(n)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return strings.ToLower(t.Name())
+}
+
+// Dispatcher fans events out to several notifiers without blocking the
+// detector. Each notifier gets its own queue so a slow sink (e.g. a webhook
+// that is retrying) cannot hold up the others.
+type Dispatcher struct {
+	sinks  []Notifier
+	queues []chan queuedEvent
+	wg     sync.WaitGroup
+
+	// ctx is cancelled when Close runs out of time, aborting retries
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// queuedEvent links the delivery span back to the span that queued the event
+type queuedEvent struct {
+	event Event
+	link  trace.Link
+}
+
+var tracer = otel.Tracer("github.com/Maniratnam557/k8s-pod-detective/pkg/notifier")
+
+const dispatchQueueSize = 256
+
+func NewDispatcher(sinks ...Notifier) *Dispatcher {
+	d := &Dispatcher{sinks: sinks}
+	d.ctx, d.cancel = context.WithCancel(context.Background())
+	for _, sink := range sinks {
+		queue := make(chan queuedEvent, dispatchQueueSize)
+		d.queues = append(d.queues, queue)
+
+		d.wg.Add(1)
+		go func(sink Notifier, queue chan queuedEvent) {
+			defer d.wg.Done()
+			for queued := range queue {
+				d.deliver(sink, queued)
+			}
+		}(sink, queue)
+	}
+	return d
+}
+
+// Notify queues the event for every notifier. It only fails if a queue is full.
+func (d *Dispatcher) Notify(ctx context.Context, event Event) error {
+	queued := queuedEvent{
+		event: event,
+		link:  trace.LinkFromContext(ctx),
+	}
+
+	var dropped int
+	for _, queue := range d.queues {
+		select {
+		case queue <- queued:
+		default:
+			dropped++
+		}
+	}
+	if dropped > 0 {
+		metrics.NotifierFailures.WithLabelValues("dispatcher").Add(float64(dropped))
+		return fmt.Errorf("dropped %s event for %s/%s: %d notifier queue(s) full",
+			event.Type, event.Failure.Namespace, event.Failure.PodName, dropped)
+	}
+	return nil
+}
+
+func (d *Dispatcher) deliver(sink Notifier, queued queuedEvent) {
+	ctx, span := tracer.Start(d.ctx, "notifier.deliver",
+		trace.WithLinks(queued.link),
+		trace.WithAttributes(
+			attribute.String("pod_detective.notifier", Name(sink)),
+			attribute.String("pod_detective.event", string(queued.event.Type)),
+		))
+	defer span.End()
+
+	if err := sink.Notify(ctx, queued.event); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "delivery failed")
+		metrics.NotifierFailures.WithLabelValues(Name(sink)).Inc()
+		slog.Warn("Notifier failed", "notifier", Name(sink), "event", queued.event.Type, "error", err)
+	}
+}
+
+// Close stops accepting events, waits for queued ones to be delivered and
+// closes every notifier that holds resources of its own. If ctx ends first,
+// retries in flight are cancelled and whatever is still queued fails fast, so
+// webhooks write it to their dead-letter file instead of delivering it.
+func (d *Dispatcher) Close(ctx context.Context) error {
+	for _, queue := range d.queues {
+		close(queue)
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(drained)
+	}()
+
+	var err error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		d.cancel()
+		<-drained
+		err = fmt.Errorf("stopped delivering queued events: %w", ctx.Err())
+	}
+	d.cancel()
+
+	for _, sink := range d.sinks {
+		if closer, ok := sink.(io.Closer); ok {
+			closer.Close()
+		}
+	}
+	return err
+}
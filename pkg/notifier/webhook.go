@@ -0,0 +1,189 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/explainer"
+)
+
+// SignatureHeader carries the hex HMAC-SHA256 of the request body, prefixed
+// with "sha256=" so receivers can verify the payload came from the detector.
+const SignatureHeader = "X-Detective-Signature"
+
+// WebhookConfig configures a generic HTTP webhook sink
+type WebhookConfig struct {
+	URL            string
+	Secret         string
+	Headers        map[string]string
+	Timeout        time.Duration
+	MaxRetries     int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	DeadLetterPath string // JSONL file for undeliverable payloads; empty disables it
+}
+
+// Webhook POSTs a JSON payload for every failure and recovery
+type Webhook struct {
+	config WebhookConfig
+	client *http.Client
+	mu     sync.Mutex // guards the dead-letter file
+}
+
+type webhookPayload struct {
+	Event     EventType             `json:"event"`
+	Timestamp time.Time             `json:"timestamp"`
+	Failure   explainer.FailureInfo `json:"failure"`
+	Diagnosis string                `json:"diagnosis"`
+	Recovery  *Recovery             `json:"recovery,omitempty"`
+}
+
+type deadLetter struct {
+	Timestamp time.Time       `json:"timestamp"`
+	URL       string          `json:"url"`
+	Attempts  int             `json:"attempts"`
+	Error     string          `json:"error"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+func NewWebhook(config WebhookConfig) (*Webhook, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("webhook URL is required")
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.InitialBackoff == 0 {
+		config.InitialBackoff = time.Second
+	}
+	if config.MaxBackoff == 0 {
+		config.MaxBackoff = 30 * time.Second
+	}
+
+	return &Webhook{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+	}, nil
+}
+
+// Notify delivers the event, retrying with exponential backoff. Payloads that
+// still cannot be delivered are appended to the dead-letter file.
+func (w *Webhook) Notify(ctx context.Context, event Event) error {
+	body, err := json.Marshal(webhookPayload{
+		Event:     event.Type,
+		Timestamp: event.Time,
+		Failure:   event.Failure,
+		Diagnosis: event.Diagnosis,
+		Recovery:  event.Recovery,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	backoff := w.config.InitialBackoff
+	attempts := 0
+	for {
+		attempts++
+		retry, err := w.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+
+		if !retry || attempts > w.config.MaxRetries {
+			return w.deadLetter(body, attempts, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return w.deadLetter(body, attempts, ctx.Err())
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > w.config.MaxBackoff {
+			backoff = w.config.MaxBackoff
+		}
+	}
+}
+
+// post sends one request and reports whether a failure is worth retrying
+func (w *Webhook) post(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range w.config.Headers {
+		req.Header.Set(name, value)
+	}
+	if w.config.Secret != "" {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(w.config.Secret, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("webhook returned %s", resp.Status)
+	default:
+		return false, fmt.Errorf("webhook returned %s", resp.Status)
+	}
+}
+
+func (w *Webhook) deadLetter(body []byte, attempts int, cause error) error {
+	if w.config.DeadLetterPath == "" {
+		return fmt.Errorf("webhook delivery failed after %d attempt(s): %w", attempts, cause)
+	}
+
+	line, err := json.Marshal(deadLetter{
+		Timestamp: time.Now().UTC(),
+		URL:       w.config.URL,
+		Attempts:  attempts,
+		Error:     cause.Error(),
+		Payload:   body,
+	})
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	f, err := os.OpenFile(w.config.DeadLetterPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("webhook delivery failed (%v) and dead-letter file is unavailable: %w", cause, err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("webhook delivery failed (%v) and dead-letter write failed: %w", cause, err)
+	}
+
+	return fmt.Errorf("webhook delivery failed after %d attempt(s), payload written to %s: %w",
+		attempts, w.config.DeadLetterPath, cause)
+}
+
+// Sign returns the hex-encoded HMAC-SHA256 of body using secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,204 @@
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/explainer"
+)
+
+func failure(pod string) Event {
+	return Event{
+		Type: EventFailure,
+		Time: time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC),
+		Failure: explainer.FailureInfo{
+			Namespace:     "shop",
+			PodName:       pod,
+			ContainerName: "app",
+			Reason:        "CrashLoopBackOff",
+		},
+	}
+}
+
+// readDeadLetters parses the dead-letter JSONL file
+func readDeadLetters(t *testing.T, path string) []deadLetter {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var letters []deadLetter
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var letter deadLetter
+		if err := json.Unmarshal(scanner.Bytes(), &letter); err != nil {
+			t.Fatalf("bad dead-letter line %q: %v", scanner.Text(), err)
+		}
+		letters = append(letters, letter)
+	}
+	return letters
+}
+
+func TestWebhookSignsPayload(t *testing.T) {
+	var signature string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get(SignatureHeader)
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	webhook, err := NewWebhook(WebhookConfig{URL: server.URL, Secret: "s3cret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := webhook.Notify(context.Background(), failure("api")); err != nil {
+		t.Fatal(err)
+	}
+
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); signature != want {
+		t.Errorf("%s = %q, want %q", SignatureHeader, signature, want)
+	}
+}
+
+func TestWebhookRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int // responses in order, the last one repeats
+		wantAttempts int32
+		wantErr      bool
+	}{
+		{"success", []int{http.StatusOK}, 1, false},
+		{"server error then success", []int{http.StatusServiceUnavailable, http.StatusOK}, 2, false},
+		{"too many requests", []int{http.StatusTooManyRequests, http.StatusOK}, 2, false},
+		{"server error until retries run out", []int{http.StatusInternalServerError}, 3, true},
+		{"client error is not retried", []int{http.StatusBadRequest}, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(attempts.Add(1))
+				w.WriteHeader(tt.statuses[min(n, len(tt.statuses))-1])
+			}))
+			defer server.Close()
+
+			webhook, err := NewWebhook(WebhookConfig{
+				URL:            server.URL,
+				MaxRetries:     2,
+				InitialBackoff: time.Millisecond,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = webhook.Notify(context.Background(), failure("api"))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Notify() error = %v, want error %v", err, tt.wantErr)
+			}
+			if got := attempts.Load(); got != tt.wantAttempts {
+				t.Errorf("%d attempts, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestWebhookDeadLetter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "dead-letter.jsonl")
+	webhook, err := NewWebhook(WebhookConfig{
+		URL:            server.URL,
+		MaxRetries:     1,
+		InitialBackoff: time.Millisecond,
+		DeadLetterPath: path,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, pod := range []string{"api", "worker"} {
+		if err := webhook.Notify(context.Background(), failure(pod)); err == nil {
+			t.Fatalf("Notify(%s) succeeded against a failing webhook", pod)
+		}
+	}
+
+	letters := readDeadLetters(t, path)
+	if len(letters) != 2 {
+		t.Fatalf("%d dead letters, want 2", len(letters))
+	}
+	for i, pod := range []string{"api", "worker"} {
+		letter := letters[i]
+		if letter.URL != server.URL || letter.Attempts != 2 || letter.Error == "" {
+			t.Errorf("dead letter %d = %+v, want 2 attempts to %s with an error", i, letter, server.URL)
+		}
+
+		var payload webhookPayload
+		if err := json.Unmarshal(letter.Payload, &payload); err != nil {
+			t.Fatal(err)
+		}
+		if payload.Event != EventFailure || payload.Failure.PodName != pod {
+			t.Errorf("dead letter %d holds %s for %s, want failure for %s", i, payload.Event, payload.Failure.PodName, pod)
+		}
+	}
+}
+
+func TestDispatcherCloseDeadLettersAfterDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	// Backoff far longer than any grace period, so only Close ends the retries
+	path := filepath.Join(t.TempDir(), "dead-letter.jsonl")
+	webhook, err := NewWebhook(WebhookConfig{
+		URL:            server.URL,
+		MaxRetries:     5,
+		InitialBackoff: time.Hour,
+		DeadLetterPath: path,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dispatcher := NewDispatcher(webhook)
+	for _, pod := range []string{"api", "worker", "cron"} {
+		if err := dispatcher.Notify(context.Background(), failure(pod)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := dispatcher.Close(ctx); err == nil {
+		t.Error("Close() reported every event delivered")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Close() took %s after its deadline", elapsed)
+	}
+
+	if letters := readDeadLetters(t, path); len(letters) != 3 {
+		t.Errorf("%d dead letters, want all 3 queued events", len(letters))
+	}
+}
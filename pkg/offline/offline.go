@@ -0,0 +1,166 @@
+// Package offline reads kubectl dumps so failures can be explained without
+// access to the cluster they came from
+package offline
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// Decode reads YAML or JSON objects from r as written by 'kubectl get -o yaml'
+// or '-o json': single objects, multi-document YAML and Lists. Kinds the
+// Kubernetes client doesn't know are skipped.
+func Decode(r io.Reader) ([]runtime.Object, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(r, 4096)
+
+	var objects []runtime.Object
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objects, nil
+			}
+			return nil, err
+		}
+		if len(raw) == 0 || string(raw) == "null" {
+			continue
+		}
+
+		decoded, err := decodeObject(raw)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, decoded...)
+	}
+}
+
+func decodeObject(raw json.RawMessage) ([]runtime.Object, error) {
+	var meta metav1.TypeMeta
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return nil, err
+	}
+
+	// kubectl writes List, typed clients PodList, EventList and so on
+	if strings.HasSuffix(meta.Kind, "List") {
+		var list struct {
+			Items []json.RawMessage `json:"items"`
+		}
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", meta.Kind, err)
+		}
+
+		var objects []runtime.Object
+		for _, item := range list.Items {
+			decoded, err := decodeObject(item)
+			if err != nil {
+				return nil, err
+			}
+			objects = append(objects, decoded...)
+		}
+		return objects, nil
+	}
+
+	object, _, err := scheme.Codecs.UniversalDeserializer().Decode(raw, nil, nil)
+	if err != nil {
+		if runtime.IsNotRegisteredError(err) || runtime.IsMissingKind(err) || runtime.IsMissingVersion(err) {
+			slog.Debug("Skipping object", "kind", meta.Kind, "apiVersion", meta.APIVersion, "error", err)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to decode %s: %w", meta.Kind, err)
+	}
+	return []runtime.Object{object}, nil
+}
+
+// EventsFor returns the events about a pod, oldest first
+func EventsFor(events []corev1.Event, pod *corev1.Pod) []corev1.Event {
+	var matched []corev1.Event
+	for _, event := range events {
+		involved := event.InvolvedObject
+		if involved.Kind != "Pod" || involved.Name != pod.Name {
+			continue
+		}
+		if involved.Namespace != "" && involved.Namespace != pod.Namespace {
+			continue
+		}
+		// Skip events for an earlier pod with the same name
+		if involved.UID != "" && pod.UID != "" && involved.UID != pod.UID {
+			continue
+		}
+		matched = append(matched, event)
+	}
+
+	slices.SortStableFunc(matched, func(a, b corev1.Event) int {
+		return a.LastTimestamp.Time.Compare(b.LastTimestamp.Time)
+	})
+	return matched
+}
+
+// Logs serves container logs from files, for detector.Options.Logs
+type Logs struct {
+	byTarget map[string]string
+	fallback string
+}
+
+// logTailLines matches how much the detector reads from the log API
+const logTailLines = 10
+
+// Add reads a log file for target: "POD/CONTAINER", "POD", or "" for every
+// container without a more specific file
+func (l *Logs) Add(target, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if target == "" {
+		l.fallback = string(data)
+		return nil
+	}
+	if l.byTarget == nil {
+		l.byTarget = make(map[string]string)
+	}
+	l.byTarget[target] = string(data)
+	return nil
+}
+
+func (l *Logs) LastLog(ctx context.Context, namespace, podName, containerName string) (string, error) {
+	log, ok := l.byTarget[podName+"/"+containerName]
+	if !ok {
+		log, ok = l.byTarget[podName]
+	}
+	if !ok {
+		log = l.fallback
+	}
+	return tail(log, logTailLines), nil
+}
+
+// tail returns the last n lines of text
+func tail(text string, n int) string {
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
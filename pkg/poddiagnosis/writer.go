@@ -0,0 +1,202 @@
+package poddiagnosis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/apis/detective/v1alpha1"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/explainer"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/dynamic"
+)
+
+// Writer creates and updates a PodDiagnosis resource per incident. Refreshes
+// of lastSeen are throttled so a long-running failure doesn't write to the
+// API server on every poll.
+type Writer struct {
+	client         dynamic.Interface
+	updateInterval time.Duration
+
+	mu        sync.Mutex
+	lastWrite map[string]time.Time
+}
+
+func NewWriter(client dynamic.Interface, updateInterval time.Duration) *Writer {
+	return &Writer{
+		client:         client,
+		updateInterval: updateInterval,
+		lastWrite:      make(map[string]time.Time),
+	}
+}
+
+// Record creates the PodDiagnosis for a failure, or refreshes its status if
+// it already exists
+func (w *Writer) Record(ctx context.Context, pod *corev1.Pod, info explainer.FailureInfo, firstSeen, lastSeen time.Time) error {
+	name := Name(info)
+	key := pod.Namespace + "/" + name
+
+	w.mu.Lock()
+	last, written := w.lastWrite[key]
+	w.mu.Unlock()
+	if written && lastSeen.Sub(last) < w.updateInterval {
+		return nil
+	}
+
+	diagnosis := explainer.Diagnose(info)
+	status := v1alpha1.PodDiagnosisStatus{
+		Phase:     v1alpha1.PhaseActive,
+		Reason:    info.Reason,
+		Message:   info.Message,
+		ExitCode:  info.ExitCode,
+		Summary:   diagnosis.Summary,
+		Evidence:  diagnosis.Evidence,
+		FirstSeen: metav1.NewTime(firstSeen),
+		LastSeen:  metav1.NewTime(lastSeen),
+	}
+	for _, cmd := range diagnosis.Commands {
+		status.SuggestedCommands = append(status.SuggestedCommands, cmd.Command)
+	}
+
+	resource := w.client.Resource(v1alpha1.Resource).Namespace(pod.Namespace)
+
+	existing, err := resource.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		// Owned by the pod for garbage collection, without blocking its
+		// deletion (which would need extra permissions on pods)
+		owner := metav1.NewControllerRef(pod, corev1.SchemeGroupVersion.WithKind("Pod"))
+		owner.BlockOwnerDeletion = nil
+
+		obj := &v1alpha1.PodDiagnosis{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: v1alpha1.GroupVersion.String(),
+				Kind:       "PodDiagnosis",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       pod.Namespace,
+				OwnerReferences: []metav1.OwnerReference{*owner},
+			},
+			Spec: v1alpha1.PodDiagnosisSpec{
+				PodName:       info.PodName,
+				ContainerName: info.ContainerName,
+				Workload:      info.Workload,
+			},
+		}
+
+		u, err := toUnstructured(obj)
+		if err != nil {
+			return err
+		}
+		existing, err = resource.Create(ctx, u, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to create PodDiagnosis %s: %w", key, err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("failed to get PodDiagnosis %s: %w", key, err)
+	} else if first, ok := existingFirstSeen(existing); ok && first.Before(firstSeen) {
+		// Keep the original first sighting of an ongoing failure, e.g. from
+		// before a detector restart
+		status.FirstSeen = metav1.NewTime(first)
+	}
+
+	if err := w.updateStatus(ctx, existing, status); err != nil {
+		return fmt.Errorf("failed to update PodDiagnosis %s: %w", key, err)
+	}
+
+	w.mu.Lock()
+	w.lastWrite[key] = lastSeen
+	w.mu.Unlock()
+
+	return nil
+}
+
+// Resolve marks the PodDiagnosis of a recovered failure as Resolved. A
+// missing resource is not an error: it was garbage-collected with its pod.
+func (w *Writer) Resolve(ctx context.Context, info explainer.FailureInfo, at time.Time) error {
+	name := Name(info)
+	key := info.Namespace + "/" + name
+
+	w.mu.Lock()
+	delete(w.lastWrite, key)
+	w.mu.Unlock()
+
+	resource := w.client.Resource(v1alpha1.Resource).Namespace(info.Namespace)
+	existing, err := resource.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get PodDiagnosis %s: %w", key, err)
+	}
+
+	var obj v1alpha1.PodDiagnosis
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(existing.Object, &obj); err != nil {
+		return err
+	}
+	status := obj.Status
+	status.Phase = v1alpha1.PhaseResolved
+	status.LastSeen = metav1.NewTime(at)
+
+	if err := w.updateStatus(ctx, existing, status); err != nil {
+		return fmt.Errorf("failed to resolve PodDiagnosis %s: %w", key, err)
+	}
+	return nil
+}
+
+func (w *Writer) updateStatus(ctx context.Context, obj *unstructured.Unstructured, status v1alpha1.PodDiagnosisStatus) error {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&status)
+	if err != nil {
+		return err
+	}
+	obj.Object["status"] = u
+
+	_, err = w.client.Resource(v1alpha1.Resource).Namespace(obj.GetNamespace()).
+		UpdateStatus(ctx, obj, metav1.UpdateOptions{})
+	return err
+}
+
+// Name returns the PodDiagnosis name for a failure: one per pod, container
+// and reason, shortened with a hash if it would be too long
+func Name(info explainer.FailureInfo) string {
+	name := strings.ToLower(fmt.Sprintf("%s-%s-%s", info.PodName, info.ContainerName, info.Reason))
+	if len(name) <= validation.DNS1123SubdomainMaxLength {
+		return name
+	}
+
+	sum := sha256.Sum256([]byte(name))
+	suffix := hex.EncodeToString(sum[:])[:10]
+	return name[:validation.DNS1123SubdomainMaxLength-len(suffix)-1] + "-" + suffix
+}
+
+func toUnstructured(obj *v1alpha1.PodDiagnosis) (*unstructured.Unstructured, error) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: u}, nil
+}
+
+// existingFirstSeen is the first sighting of the incident the PodDiagnosis
+// still tracks. A Resolved one belongs to an earlier incident, so a recurrence
+// starts afresh.
+func existingFirstSeen(obj *unstructured.Unstructured) (time.Time, bool) {
+	if phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase"); phase != v1alpha1.PhaseActive {
+		return time.Time{}, false
+	}
+	value, found, err := unstructured.NestedString(obj.Object, "status", "firstSeen")
+	if !found || err != nil {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	return t, err == nil
+}
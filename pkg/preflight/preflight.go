@@ -0,0 +1,169 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Features describes the configuration whose permissions are checked
+type Features struct {
+	Namespace      string
+	EmitEvents     bool
+	WriteDiagnoses bool
+	LeaderElection bool
+	LeaseNamespace string
+}
+
+// Requirement is one verb on one resource that a feature needs
+type Requirement struct {
+	Group       string
+	Resource    string
+	Subresource string
+	Verb        string
+	Namespace   string
+
+	// ClusterScoped resources, like nodes, have no namespace and can only be
+	// granted by a ClusterRole
+	ClusterScoped bool
+
+	// Feature is what stops working without the permission
+	Feature string
+	// Required permissions prevent the detector from running at all
+	Required bool
+}
+
+// ResourceName returns the resource as written in RBAC rules, e.g. "pods/log"
+func (r Requirement) ResourceName() string {
+	if r.Subresource != "" {
+		return r.Resource + "/" + r.Subresource
+	}
+	return r.Resource
+}
+
+// Requirements lists every permission the enabled features need
+func Requirements(f Features) []Requirement {
+	reqs := []Requirement{
+		{Resource: "pods", Verb: "list", Namespace: f.Namespace, Feature: "watching pods", Required: true},
+		{Resource: "pods", Subresource: "log", Verb: "get", Namespace: f.Namespace, Feature: "last log lines in explanations"},
+		{Group: "apps", Resource: "replicasets", Verb: "get", Namespace: f.Namespace, Feature: "Deployment names as workload"},
+		{Group: "batch", Resource: "jobs", Verb: "get", Namespace: f.Namespace, Feature: "CronJob names as workload"},
+		{Resource: "events", Verb: "list", Namespace: f.Namespace, Feature: "probe details in explanations"},
+		{Resource: "nodes", Verb: "get", ClusterScoped: true, Feature: "node pressure in eviction explanations"},
+	}
+
+	if f.EmitEvents {
+		reqs = append(reqs,
+			Requirement{Resource: "events", Verb: "create", Namespace: f.Namespace, Feature: "--emit-events"},
+			Requirement{Resource: "events", Verb: "patch", Namespace: f.Namespace, Feature: "--emit-events (aggregating repeated events)"},
+		)
+	}
+
+	if f.WriteDiagnoses {
+		for _, verb := range []string{"get", "create"} {
+			reqs = append(reqs, Requirement{Group: "poddetective.io", Resource: "poddiagnoses", Verb: verb, Namespace: f.Namespace, Feature: "--write-diagnoses"})
+		}
+		reqs = append(reqs, Requirement{Group: "poddetective.io", Resource: "poddiagnoses", Subresource: "status", Verb: "update", Namespace: f.Namespace, Feature: "--write-diagnoses"})
+	}
+
+	if f.LeaderElection {
+		for _, verb := range []string{"get", "create", "update"} {
+			reqs = append(reqs, Requirement{Group: "coordination.k8s.io", Resource: "leases", Verb: verb, Namespace: f.LeaseNamespace, Feature: "--leader-elect", Required: true})
+		}
+	}
+
+	return reqs
+}
+
+// Result is the outcome of checking one requirement
+type Result struct {
+	Requirement
+	Allowed bool
+	Reason  string
+}
+
+// Check runs a SelfSubjectAccessReview for every requirement
+func Check(ctx context.Context, clientset kubernetes.Interface, reqs []Requirement) ([]Result, error) {
+	results := make([]Result, 0, len(reqs))
+
+	for _, req := range reqs {
+		review := &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace:   req.Namespace,
+					Verb:        req.Verb,
+					Group:       req.Group,
+					Resource:    req.Resource,
+					Subresource: req.Subresource,
+				},
+			},
+		}
+
+		resp, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to check %s %s: %w", req.Verb, req.ResourceName(), err)
+		}
+
+		results = append(results, Result{
+			Requirement: req,
+			Allowed:     resp.Status.Allowed,
+			Reason:      resp.Status.Reason,
+		})
+	}
+
+	return results, nil
+}
+
+// Report prints the missing permissions and the features they affect. It
+// returns false if a required permission is missing.
+func Report(w io.Writer, results []Result) bool {
+	ok := true
+	var missing []Result
+	for _, result := range results {
+		if !result.Allowed {
+			missing = append(missing, result)
+			if result.Required {
+				ok = false
+			}
+		}
+	}
+
+	if len(missing) == 0 {
+		fmt.Fprintf(w, "✅ Preflight: all %d permission checks passed\n\n", len(results))
+		return true
+	}
+
+	fmt.Fprintf(w, "⚠️  Preflight: %d of %d permission checks failed\n\n", len(missing), len(results))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERB\tRESOURCE\tNAMESPACE\tIMPACT")
+	for _, result := range missing {
+		namespace := result.Namespace
+		if namespace == "" {
+			namespace = "(cluster)"
+		}
+		impact := "degraded: " + result.Feature
+		if result.Required {
+			impact = "REQUIRED: " + result.Feature
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", result.Verb, qualified(result.Requirement), namespace, impact)
+	}
+	tw.Flush()
+
+	fmt.Fprintln(w, "\nRun with the 'rbac' subcommand to print the RBAC rules that grant these permissions.")
+	fmt.Fprintln(w)
+
+	return ok
+}
+
+func qualified(req Requirement) string {
+	if req.Group == "" {
+		return req.ResourceName()
+	}
+	return req.ResourceName() + "." + req.Group
+}
@@ -0,0 +1,117 @@
+package preflight
+
+import (
+	"sort"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// RBACManifest renders the minimal rules for the requirements, bound to the
+// serviceAccount. With clusterRole it returns a single ClusterRole, otherwise
+// one Role per namespace the requirements touch. A Role can't grant
+// cluster-scoped resources, so those get a ClusterRole of their own.
+func RBACManifest(name string, reqs []Requirement, clusterRole bool, serviceAccount rbacv1.Subject) ([]byte, error) {
+	var objects []any
+
+	if clusterRole {
+		objects = append(objects, boundClusterRole(name, rules(reqs), serviceAccount)...)
+	} else {
+		byNamespace := make(map[string][]Requirement)
+		var clusterScoped []Requirement
+		for _, req := range reqs {
+			if req.ClusterScoped {
+				clusterScoped = append(clusterScoped, req)
+			} else {
+				byNamespace[req.Namespace] = append(byNamespace[req.Namespace], req)
+			}
+		}
+
+		namespaces := make([]string, 0, len(byNamespace))
+		for ns := range byNamespace {
+			namespaces = append(namespaces, ns)
+		}
+		sort.Strings(namespaces)
+
+		for _, ns := range namespaces {
+			objects = append(objects,
+				&rbacv1.Role{
+					TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "Role"},
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+					Rules:      rules(byNamespace[ns]),
+				},
+				&rbacv1.RoleBinding{
+					TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "RoleBinding"},
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+					RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: name},
+					Subjects:   []rbacv1.Subject{serviceAccount},
+				},
+			)
+		}
+
+		if len(clusterScoped) > 0 {
+			objects = append(objects, boundClusterRole(name+"-cluster", rules(clusterScoped), serviceAccount)...)
+		}
+	}
+
+	var out []byte
+	for _, obj := range objects {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, "---\n"...)
+		out = append(out, data...)
+	}
+	return out, nil
+}
+
+// boundClusterRole is a ClusterRole and the ClusterRoleBinding granting it
+func boundClusterRole(name string, rules []rbacv1.PolicyRule, subject rbacv1.Subject) []any {
+	return []any{
+		&rbacv1.ClusterRole{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Rules:      rules,
+		},
+		&rbacv1.ClusterRoleBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRoleBinding"},
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: name},
+			Subjects:   []rbacv1.Subject{subject},
+		},
+	}
+}
+
+// rules merges requirements into one rule per API group and resource
+func rules(reqs []Requirement) []rbacv1.PolicyRule {
+	type key struct{ group, resource string }
+	verbs := make(map[key]map[string]bool)
+	var order []key
+
+	for _, req := range reqs {
+		k := key{req.Group, req.ResourceName()}
+		if verbs[k] == nil {
+			verbs[k] = make(map[string]bool)
+			order = append(order, k)
+		}
+		verbs[k][req.Verb] = true
+	}
+
+	var result []rbacv1.PolicyRule
+	for _, k := range order {
+		var list []string
+		for verb := range verbs[k] {
+			list = append(list, verb)
+		}
+		sort.Strings(list)
+
+		result = append(result, rbacv1.PolicyRule{
+			APIGroups: []string{k.group},
+			Resources: []string{k.resource},
+			Verbs:     list,
+		})
+	}
+	return result
+}
@@ -0,0 +1,121 @@
+package preflight
+
+import (
+	"strings"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// manifest is a parsed RBACManifest, keyed by namespace/name
+type manifest struct {
+	roles               map[string]rbacv1.Role
+	clusterRoles        map[string]rbacv1.ClusterRole
+	roleBindings        map[string]rbacv1.RoleBinding
+	clusterRoleBindings map[string]rbacv1.ClusterRoleBinding
+}
+
+func parseManifest(t *testing.T, data []byte) manifest {
+	t.Helper()
+	m := manifest{
+		roles:               make(map[string]rbacv1.Role),
+		clusterRoles:        make(map[string]rbacv1.ClusterRole),
+		roleBindings:        make(map[string]rbacv1.RoleBinding),
+		clusterRoleBindings: make(map[string]rbacv1.ClusterRoleBinding),
+	}
+	for _, doc := range strings.Split(string(data), "---\n")[1:] {
+		var kind struct{ Kind string }
+		if err := yaml.Unmarshal([]byte(doc), &kind); err != nil {
+			t.Fatal(err)
+		}
+		var err error
+		switch kind.Kind {
+		case "Role":
+			var role rbacv1.Role
+			err = yaml.Unmarshal([]byte(doc), &role)
+			m.roles[role.Namespace+"/"+role.Name] = role
+		case "ClusterRole":
+			var role rbacv1.ClusterRole
+			err = yaml.Unmarshal([]byte(doc), &role)
+			m.clusterRoles[role.Name] = role
+		case "RoleBinding":
+			var binding rbacv1.RoleBinding
+			err = yaml.Unmarshal([]byte(doc), &binding)
+			m.roleBindings[binding.Namespace+"/"+binding.Name] = binding
+		case "ClusterRoleBinding":
+			var binding rbacv1.ClusterRoleBinding
+			err = yaml.Unmarshal([]byte(doc), &binding)
+			m.clusterRoleBindings[binding.Name] = binding
+		default:
+			t.Fatalf("unexpected %s", kind.Kind)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return m
+}
+
+// bound reports whether a binding grants the role to just the subject
+func bound(ref rbacv1.RoleRef, subjects []rbacv1.Subject, kind, name string, subject rbacv1.Subject) bool {
+	return ref.APIGroup == rbacv1.GroupName && ref.Kind == kind && ref.Name == name &&
+		len(subjects) == 1 && subjects[0] == subject
+}
+
+func TestRBACManifestGrantsNodesClusterWide(t *testing.T) {
+	reqs := Requirements(Features{Namespace: "demo", LeaderElection: true, LeaseNamespace: "ops"})
+	subject := rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Namespace: "ops", Name: "detective"}
+
+	tests := []struct {
+		name         string
+		clusterRole  bool
+		roles        int
+		clusterRoles int
+	}{
+		{"namespaced roles", false, 2, 1},
+		{"cluster role", true, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := RBACManifest("k8s-pod-detective", reqs, tt.clusterRole, subject)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m := parseManifest(t, data)
+
+			if len(m.roles) != tt.roles || len(m.clusterRoles) != tt.clusterRoles {
+				t.Fatalf("%d Roles and %d ClusterRoles, want %d and %d", len(m.roles), len(m.clusterRoles), tt.roles, tt.clusterRoles)
+			}
+
+			for key, role := range m.roles {
+				for _, rule := range role.Rules {
+					if rule.Resources[0] == "nodes" {
+						t.Errorf("Role %s grants nodes, which a Role can't", key)
+					}
+				}
+				binding, ok := m.roleBindings[key]
+				if !ok || !bound(binding.RoleRef, binding.Subjects, "Role", role.Name, subject) {
+					t.Errorf("Role %s isn't bound to %+v: %+v", key, subject, binding)
+				}
+			}
+			if len(m.roleBindings) != len(m.roles) {
+				t.Errorf("%d RoleBindings for %d Roles", len(m.roleBindings), len(m.roles))
+			}
+
+			for name, role := range m.clusterRoles {
+				if !tt.clusterRole && (len(role.Rules) != 1 || role.Rules[0].Resources[0] != "nodes" || role.Rules[0].Verbs[0] != "get") {
+					t.Errorf("ClusterRole rules = %+v, want get nodes", role.Rules)
+				}
+				binding, ok := m.clusterRoleBindings[name]
+				if !ok || !bound(binding.RoleRef, binding.Subjects, "ClusterRole", name, subject) {
+					t.Errorf("ClusterRole %s isn't bound to %+v: %+v", name, subject, binding)
+				}
+			}
+			if len(m.clusterRoleBindings) != len(m.clusterRoles) {
+				t.Errorf("%d ClusterRoleBindings for %d ClusterRoles", len(m.clusterRoleBindings), len(m.clusterRoles))
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+// Package recording writes everything the detector observes to a file and
+// reads it back, so an incident can be replayed after the fact
+package recording
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/explainer"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/notifier"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// Version is the file format version written by this build
+const Version = 1
+
+// Header is the first entry of a recording and describes how the detector ran
+type Header struct {
+	Version       int           `json:"version"`
+	Namespace     string        `json:"namespace"`
+	PodName       string        `json:"podName,omitempty"`
+	LabelSelector string        `json:"labelSelector,omitempty"`
+	Interval      time.Duration `json:"interval"`
+	Cluster       string        `json:"cluster,omitempty"`
+}
+
+// Kind says what an entry holds
+type Kind string
+
+const (
+	KindHeader Kind = "header"
+	KindPods   Kind = "pods"   // the result of one pod list
+	KindLog    Kind = "log"    // one log fetch
+	KindObject Kind = "object" // a ReplicaSet or Job read to resolve a workload
+	KindEvent  Kind = "event"  // a failure or recovery the detector reported
+)
+
+// Entry is one line of a recording
+type Entry struct {
+	Time time.Time `json:"time"`
+	Kind Kind      `json:"kind"`
+
+	Header *Header `json:"header,omitempty"`
+
+	Namespace string          `json:"namespace,omitempty"`
+	Pods      *corev1.PodList `json:"pods,omitempty"`
+
+	Pod       string `json:"pod,omitempty"`
+	Container string `json:"container,omitempty"`
+	Log       string `json:"log,omitempty"`
+	Error     string `json:"error,omitempty"`
+
+	Object json.RawMessage `json:"object,omitempty"`
+
+	EventType notifier.EventType     `json:"eventType,omitempty"`
+	Failure   *explainer.FailureInfo `json:"failure,omitempty"`
+	Recovery  *notifier.Recovery     `json:"recovery,omitempty"`
+}
+
+// Writer records detector observations as gzipped JSON lines. It implements
+// detector.Observer.
+type Writer struct {
+	mu      sync.Mutex
+	file    *os.File
+	gz      *gzip.Writer
+	encoder *json.Encoder
+	failed  bool
+	now     func() time.Time
+}
+
+// Create starts a recording at path
+func Create(path string, header Header) (*Writer, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording: %w", err)
+	}
+
+	gz := gzip.NewWriter(file)
+	w := &Writer{file: file, gz: gz, encoder: json.NewEncoder(gz), now: time.Now}
+
+	header.Version = Version
+	w.write(Entry{Kind: KindHeader, Header: &header})
+	if w.failed {
+		file.Close()
+		return nil, fmt.Errorf("failed to write recording header to %s", path)
+	}
+	return w, nil
+}
+
+func (w *Writer) write(entry Entry) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.failed {
+		return
+	}
+	entry.Time = w.now().UTC()
+	if err := w.encoder.Encode(entry); err != nil {
+		// Keep detecting; a broken recording shouldn't stop the detector
+		slog.Error("Failed to write recording, recording stopped", "file", w.file.Name(), "error", err)
+		w.failed = true
+	}
+}
+
+func (w *Writer) ObservePods(namespace string, pods *corev1.PodList) {
+	w.write(Entry{Kind: KindPods, Namespace: namespace, Pods: pods})
+
+	// Flush once per poll, so a killed process loses at most the last one
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.failed {
+		w.gz.Flush()
+	}
+}
+
+func (w *Writer) ObserveLog(namespace, podName, containerName, log string, err error) {
+	entry := Entry{Kind: KindLog, Namespace: namespace, Pod: podName, Container: containerName, Log: log}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	w.write(entry)
+}
+
+func (w *Writer) ObserveObject(object runtime.Object) {
+	// Typed clients drop apiVersion and kind, which replay needs to decode
+	object = object.DeepCopyObject()
+	if kinds, _, err := scheme.Scheme.ObjectKinds(object); err == nil && len(kinds) > 0 {
+		object.GetObjectKind().SetGroupVersionKind(kinds[0])
+	}
+
+	data, err := json.Marshal(object)
+	if err != nil {
+		slog.Warn("Failed to record object", "error", err)
+		return
+	}
+	w.write(Entry{Kind: KindObject, Object: data})
+}
+
+func (w *Writer) ObserveEvent(event notifier.Event) {
+	w.write(Entry{Kind: KindEvent, EventType: event.Type, Failure: &event.Failure, Recovery: event.Recovery})
+}
+
+// Close flushes and closes the recording
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	err := w.gz.Close()
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Reader reads a recording back
+type Reader struct {
+	Header Header
+
+	file    *os.File
+	gz      *gzip.Reader
+	decoder *json.Decoder
+}
+
+// Open opens a recording and reads its header
+func Open(path string) (*Reader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("%s is not a recording: %w", path, err)
+	}
+
+	r := &Reader{file: file, gz: gz, decoder: json.NewDecoder(gz)}
+	first, err := r.Next()
+	if err != nil || first.Kind != KindHeader || first.Header == nil {
+		r.Close()
+		return nil, fmt.Errorf("%s is not a recording: missing header", path)
+	}
+	if first.Header.Version > Version {
+		r.Close()
+		return nil, fmt.Errorf("%s has format version %d, this build reads up to %d", path, first.Header.Version, Version)
+	}
+	r.Header = *first.Header
+	return r, nil
+}
+
+// Next returns the next entry, or io.EOF at the end. A recording cut short
+// by a killed process ends at its last complete entry.
+func (r *Reader) Next() (*Entry, error) {
+	var entry Entry
+	if err := r.decoder.Decode(&entry); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			slog.Warn("Recording ends abruptly, replaying up to its last complete entry")
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *Reader) Close() error {
+	r.gz.Close()
+	return r.file.Close()
+}
@@ -0,0 +1,268 @@
+package recording
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/detector"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/explainer"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/notifier"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/offline"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// ReplayOptions tunes a replay
+type ReplayOptions struct {
+	// Speed multiplies the recorded pace, e.g. 10 for ten times faster;
+	// 0 replays as fast as possible
+	Speed float64
+
+	// Output receives the detector's explanations (default os.Stdout)
+	Output io.Writer
+}
+
+// ReplayResult compares the replayed failures and recoveries with the
+// recorded ones
+type ReplayResult struct {
+	Polls    int
+	Recorded int
+	Replayed int
+
+	// Mismatches describes every event only one of the two runs reported
+	Mismatches []string
+}
+
+// Replay feeds a recording through a fake clientset into a fresh detector
+func Replay(ctx context.Context, r *Reader, opts ReplayOptions) (*ReplayResult, error) {
+	clientset := fake.NewClientset()
+	logs := &replayLogs{}
+	events := &eventCollector{}
+
+	// The detector sees time pass as it did when recording
+	var now time.Time
+	podDetector := detector.New(clientset, detector.Options{
+		PodName:       r.Header.PodName,
+		LabelSelector: r.Header.LabelSelector,
+		Cluster:       r.Header.Cluster,
+		Output:        opts.Output,
+		Logs:          logs,
+		Observer:      events,
+		Clock:         func() time.Time { return now },
+	})
+
+	result := &ReplayResult{}
+	var (
+		snapshot *Entry   // the pod list of the poll being collected
+		related  []*Entry // logs, objects and events recorded during that poll
+		previous time.Time
+	)
+
+	// replayPoll loads what the recorded poll read into the fake cluster, polls
+	// once and compares what the detector reports
+	replayPoll := func() error {
+		if snapshot == nil {
+			return nil
+		}
+
+		var recorded []string
+		for _, entry := range related {
+			switch entry.Kind {
+			case KindLog:
+				logs.set(entry)
+			case KindObject:
+				if err := addObject(clientset, entry.Object); err != nil {
+					return err
+				}
+			case KindEvent:
+				if entry.Failure != nil {
+					recorded = append(recorded, eventKey(entry.EventType, *entry.Failure))
+				}
+			}
+		}
+
+		if err := syncPods(ctx, clientset, snapshot.Namespace, snapshot.Pods); err != nil {
+			return err
+		}
+		now = snapshot.Time
+		if err := podDetector.Poll(ctx, snapshot.Namespace); err != nil {
+			return err
+		}
+		result.Polls++
+
+		replayed := events.take()
+		result.Recorded += len(recorded)
+		result.Replayed += len(replayed)
+		at := snapshot.Time.Format(time.RFC3339)
+		for _, key := range recorded {
+			if !slices.Contains(replayed, key) {
+				result.Mismatches = append(result.Mismatches, fmt.Sprintf("%s: recorded %s, not reported on replay", at, key))
+			}
+		}
+		for _, key := range replayed {
+			if !slices.Contains(recorded, key) {
+				result.Mismatches = append(result.Mismatches, fmt.Sprintf("%s: reported %s on replay, not in the recording", at, key))
+			}
+		}
+		return nil
+	}
+
+	for {
+		entry, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("failed to read recording: %w", err)
+		}
+
+		if entry.Kind != KindPods {
+			related = append(related, entry)
+			continue
+		}
+
+		if err := replayPoll(); err != nil {
+			return result, err
+		}
+
+		// Keep the recorded pace between polls
+		if !previous.IsZero() && opts.Speed > 0 {
+			delay := time.Duration(float64(entry.Time.Sub(previous)) / opts.Speed)
+			select {
+			case <-ctx.Done():
+				return result, ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+		previous = entry.Time
+		snapshot, related = entry, nil
+	}
+
+	return result, replayPoll()
+}
+
+// eventKey identifies a failure or recovery across runs
+func eventKey(eventType notifier.EventType, info explainer.FailureInfo) string {
+	return fmt.Sprintf("%s %s/%s container %s (%s)", eventType, info.Namespace, info.PodName, info.ContainerName, info.Reason)
+}
+
+// syncPods makes the fake cluster hold exactly the recorded pods
+func syncPods(ctx context.Context, clientset *fake.Clientset, namespace string, pods *corev1.PodList) error {
+	existing, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	wanted := make(map[string]bool)
+	if pods != nil {
+		for i := range pods.Items {
+			pod := pods.Items[i].DeepCopy()
+			pod.ResourceVersion = ""
+			wanted[pod.Namespace+"/"+pod.Name] = true
+
+			_, err := clientset.CoreV1().Pods(pod.Namespace).Update(ctx, pod, metav1.UpdateOptions{})
+			if apierrors.IsNotFound(err) {
+				_, err = clientset.CoreV1().Pods(pod.Namespace).Create(ctx, pod, metav1.CreateOptions{})
+			}
+			if err != nil {
+				return fmt.Errorf("failed to load pod %s/%s: %w", pod.Namespace, pod.Name, err)
+			}
+		}
+	}
+
+	for _, pod := range existing.Items {
+		if !wanted[pod.Namespace+"/"+pod.Name] {
+			if err := clientset.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addObject loads a recorded ReplicaSet, Job or Event into the fake cluster,
+// replacing the version recorded before it
+func addObject(clientset *fake.Clientset, data []byte) error {
+	objects, err := offline.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to decode recorded object: %w", err)
+	}
+	for _, object := range objects {
+		err := clientset.Tracker().Add(object)
+		if apierrors.IsAlreadyExists(err) {
+			err = updateObject(clientset, object)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func updateObject(clientset *fake.Clientset, object runtime.Object) error {
+	kinds, _, err := scheme.Scheme.ObjectKinds(object)
+	if err != nil {
+		return err
+	}
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		return err
+	}
+	accessor.SetResourceVersion("")
+
+	resource, _ := meta.UnsafeGuessKindToResource(kinds[0])
+	return clientset.Tracker().Update(resource, object, accessor.GetNamespace())
+}
+
+// replayLogs serves the most recently recorded log of each container
+type replayLogs struct {
+	logs map[string]*Entry
+}
+
+func (l *replayLogs) set(entry *Entry) {
+	if l.logs == nil {
+		l.logs = make(map[string]*Entry)
+	}
+	l.logs[entry.Namespace+"/"+entry.Pod+"/"+entry.Container] = entry
+}
+
+func (l *replayLogs) LastLog(ctx context.Context, namespace, podName, containerName string) (string, error) {
+	entry, ok := l.logs[namespace+"/"+podName+"/"+containerName]
+	if !ok {
+		return "", fmt.Errorf("no log recorded for %s/%s container %s", namespace, podName, containerName)
+	}
+	if entry.Error != "" {
+		return "", errors.New(entry.Error)
+	}
+	return entry.Log, nil
+}
+
+// eventCollector keeps the events of the current replayed poll
+type eventCollector struct {
+	keys []string
+}
+
+func (c *eventCollector) ObservePods(string, *corev1.PodList)              {}
+func (c *eventCollector) ObserveLog(string, string, string, string, error) {}
+func (c *eventCollector) ObserveObject(runtime.Object)                     {}
+
+func (c *eventCollector) ObserveEvent(event notifier.Event) {
+	c.keys = append(c.keys, eventKey(event.Type, event.Failure))
+}
+
+func (c *eventCollector) take() []string {
+	keys := c.keys
+	c.keys = nil
+	return keys
+}
@@ -0,0 +1,127 @@
+package recording
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/detector"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// unreadyPod has run since startedAt without passing its readiness probe,
+// which gives it 40s before it counts as failing
+func unreadyPod(startedAt time.Time) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop", UID: "1b2c"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name: "app",
+			ReadinessProbe: &corev1.Probe{
+				ProbeHandler:     corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/ready", Port: intstr.FromInt32(8080)}},
+				PeriodSeconds:    10,
+				FailureThreshold: 3,
+			},
+		}}},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "app",
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(startedAt)}},
+			}},
+		},
+	}
+}
+
+func TestReplayReproducesRecording(t *testing.T) {
+	// Recorded yesterday, replayed now
+	startedAt := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+	clock := startedAt
+
+	path := filepath.Join(t.TempDir(), "incident.jsonl.gz")
+	w, err := Create(path, Header{Namespace: "shop", Interval: 10 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.now = func() time.Time { return clock }
+
+	pod := unreadyPod(startedAt)
+	clientset := fake.NewClientset(pod)
+	live := detector.New(clientset, detector.Options{
+		Output:   io.Discard,
+		Observer: w,
+		Clock:    func() time.Time { return clock },
+	})
+
+	// Still starting, then failing its readiness probe, then ready
+	ctx := context.Background()
+	for _, step := range []struct {
+		at    time.Duration
+		ready bool
+	}{{10 * time.Second, false}, {60 * time.Second, false}, {90 * time.Second, true}} {
+		clock = startedAt.Add(step.at)
+		pod.Status.ContainerStatuses[0].Ready = step.ready
+		if _, err := clientset.CoreV1().Pods("shop").UpdateStatus(ctx, pod, metav1.UpdateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		if err := live.Poll(ctx, "shop"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	result, err := Replay(ctx, r, ReplayOptions{Output: io.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Polls != 3 || result.Recorded != 2 || result.Replayed != 2 {
+		t.Errorf("replayed %d polls with %d of %d recorded events, want 3 polls with 2 of 2",
+			result.Polls, result.Replayed, result.Recorded)
+	}
+	for _, mismatch := range result.Mismatches {
+		t.Error(mismatch)
+	}
+}
+
+func TestAddObjectReplacesEarlierVersion(t *testing.T) {
+	clientset := fake.NewClientset()
+	event := &corev1.Event{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Event"},
+		ObjectMeta: metav1.ObjectMeta{Name: "api.unhealthy", Namespace: "shop", ResourceVersion: "41"},
+		Reason:     "Unhealthy",
+		Count:      3,
+	}
+
+	for _, count := range []int32{3, 18} {
+		event.Count = count
+		data, err := json.Marshal(event)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := addObject(clientset, data); err != nil {
+			t.Fatalf("adding version with count %d: %v", count, err)
+		}
+	}
+
+	got, err := clientset.CoreV1().Events("shop").Get(context.Background(), "api.unhealthy", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Count != 18 {
+		t.Errorf("event count = %d, want the later recorded 18", got.Count)
+	}
+}
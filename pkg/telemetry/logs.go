@@ -0,0 +1,137 @@
+package telemetry
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/explainer"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/notifier"
+
+	"go.opentelemetry.io/otel/attribute"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+)
+
+const loggerName = "github.com/Maniratnam557/k8s-pod-detective"
+
+// LogNotifier emits every failure and recovery as an OTLP log record. The
+// failing pod is described by the record's resource (k8s.namespace.name,
+// k8s.pod.name, k8s.container.name, k8s.deployment.name, ...), so backends
+// correlate it with the pod's own telemetry.
+type LogNotifier struct {
+	provider *Provider
+
+	// A LoggerProvider carries a single resource, so there is one per failing
+	// container. They all share the provider's batch processor.
+	mu      sync.Mutex
+	loggers map[string]otellog.Logger
+}
+
+// LogNotifier returns a notifier that exports events as OTLP log records
+func (p *Provider) LogNotifier() *LogNotifier {
+	return &LogNotifier{
+		provider: p,
+		loggers:  make(map[string]otellog.Logger),
+	}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, event notifier.Event) error {
+	info := event.Failure
+
+	var record otellog.Record
+	record.SetTimestamp(event.Time)
+	record.SetEventName("pod_detective." + string(event.Type))
+	record.AddAttributes(
+		attribute.String("pod_detective.reason", info.Reason),
+		attribute.Int("pod_detective.exit_code", int(info.ExitCode)),
+	)
+
+	switch event.Type {
+	case notifier.EventFailure:
+		record.SetSeverity(otellog.SeverityError)
+		record.SetSeverityText("ERROR")
+		record.SetBody(attribute.StringValue(explainer.Summary(info)))
+		if info.Message != "" {
+			record.AddAttributes(attribute.String("pod_detective.message", info.Message))
+		}
+		if event.Diagnosis != "" {
+			record.AddAttributes(attribute.String("pod_detective.explanation", event.Diagnosis))
+		}
+	case notifier.EventRecovery:
+		record.SetSeverity(otellog.SeverityInfo)
+		record.SetSeverityText("INFO")
+		body := "Recovered: " + explainer.Summary(info)
+		if recovery := event.Recovery; recovery != nil {
+			body += ", " + recovery.String()
+			record.AddAttributes(
+				attribute.Float64("pod_detective.recovery.duration", recovery.DurationSeconds),
+				attribute.Int("pod_detective.recovery.restarts", int(recovery.Restarts)),
+			)
+		}
+		record.SetBody(attribute.StringValue(body))
+	}
+
+	n.logger(info, event.Type == notifier.EventRecovery).Emit(ctx, record)
+	return nil
+}
+
+// logger returns the logger for a container, dropping it after a recovery
+// so the map doesn't grow with pod churn
+func (n *LogNotifier) logger(info explainer.FailureInfo, last bool) otellog.Logger {
+	key := info.Cluster + "/" + info.Namespace + "/" + info.PodName + "/" + info.ContainerName
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	logger, ok := n.loggers[key]
+	if !ok {
+		res, err := resource.Merge(n.provider.resource, resource.NewSchemaless(k8sAttributes(info)...))
+		if err != nil {
+			res = n.provider.resource
+		}
+		logger = sdklog.NewLoggerProvider(
+			sdklog.WithResource(res),
+			sdklog.WithProcessor(n.provider.logProcessor),
+		).Logger(loggerName)
+	}
+
+	if last {
+		delete(n.loggers, key)
+	} else {
+		n.loggers[key] = logger
+	}
+	return logger
+}
+
+// k8sAttributes describes the failing pod with the k8s.* semantic conventions
+func k8sAttributes(info explainer.FailureInfo) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		semconv.K8SNamespaceName(info.Namespace),
+		semconv.K8SPodName(info.PodName),
+		semconv.K8SContainerName(info.ContainerName),
+	}
+	if info.Cluster != "" {
+		attrs = append(attrs, semconv.K8SClusterName(info.Cluster))
+	}
+
+	kind, name, _ := strings.Cut(info.Workload, "/")
+	switch kind {
+	case "Deployment":
+		attrs = append(attrs, semconv.K8SDeploymentName(name))
+	case "StatefulSet":
+		attrs = append(attrs, semconv.K8SStatefulSetName(name))
+	case "DaemonSet":
+		attrs = append(attrs, semconv.K8SDaemonSetName(name))
+	case "ReplicaSet":
+		attrs = append(attrs, semconv.K8SReplicaSetName(name))
+	case "Job":
+		attrs = append(attrs, semconv.K8SJobName(name))
+	case "CronJob":
+		attrs = append(attrs, semconv.K8SCronJobName(name))
+	}
+
+	return attrs
+}
@@ -0,0 +1,147 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+)
+
+// ServiceName identifies the detector in exported telemetry
+const ServiceName = "k8s-pod-detective"
+
+// Config configures the OTLP exporters
+type Config struct {
+	// Endpoint is host:port or a full URL. Empty falls back to the standard
+	// OTEL_EXPORTER_OTLP_* environment variables.
+	Endpoint string
+	Protocol string // "grpc" or "http"
+	Insecure bool
+}
+
+// Provider owns the trace and log pipelines
+type Provider struct {
+	tracerProvider *sdktrace.TracerProvider
+	logProcessor   sdklog.Processor
+	resource       *resource.Resource
+}
+
+// Setup creates the OTLP exporters and installs the global tracer provider,
+// so the detector's spans are exported from then on
+func Setup(ctx context.Context, config Config) (*Provider, error) {
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName(ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build resource: %w", err)
+	}
+
+	var (
+		traceExporter sdktrace.SpanExporter
+		logExporter   sdklog.Exporter
+	)
+
+	switch config.Protocol {
+	case "grpc", "":
+		traceExporter, err = otlptracegrpc.New(ctx, grpcTraceOptions(config)...)
+		if err == nil {
+			logExporter, err = otlploggrpc.New(ctx, grpcLogOptions(config)...)
+		}
+	case "http":
+		traceExporter, err = otlptracehttp.New(ctx, httpTraceOptions(config)...)
+		if err == nil {
+			logExporter, err = otlploghttp.New(ctx, httpLogOptions(config)...)
+		}
+	default:
+		return nil, fmt.Errorf("unknown OTLP protocol %q, expected grpc or http", config.Protocol)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(traceExporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tracerProvider)
+
+	return &Provider{
+		tracerProvider: tracerProvider,
+		logProcessor:   sdklog.NewBatchProcessor(logExporter),
+		resource:       res,
+	}, nil
+}
+
+// Shutdown flushes and stops both pipelines
+func (p *Provider) Shutdown(ctx context.Context) error {
+	return errors.Join(
+		p.tracerProvider.Shutdown(ctx),
+		p.logProcessor.Shutdown(ctx),
+	)
+}
+
+func isURL(endpoint string) bool {
+	return strings.Contains(endpoint, "://")
+}
+
+func grpcTraceOptions(config Config) []otlptracegrpc.Option {
+	var opts []otlptracegrpc.Option
+	if isURL(config.Endpoint) {
+		opts = append(opts, otlptracegrpc.WithEndpointURL(config.Endpoint))
+	} else if config.Endpoint != "" {
+		opts = append(opts, otlptracegrpc.WithEndpoint(config.Endpoint))
+	}
+	if config.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	return opts
+}
+
+func grpcLogOptions(config Config) []otlploggrpc.Option {
+	var opts []otlploggrpc.Option
+	if isURL(config.Endpoint) {
+		opts = append(opts, otlploggrpc.WithEndpointURL(config.Endpoint))
+	} else if config.Endpoint != "" {
+		opts = append(opts, otlploggrpc.WithEndpoint(config.Endpoint))
+	}
+	if config.Insecure {
+		opts = append(opts, otlploggrpc.WithInsecure())
+	}
+	return opts
+}
+
+func httpTraceOptions(config Config) []otlptracehttp.Option {
+	var opts []otlptracehttp.Option
+	if isURL(config.Endpoint) {
+		opts = append(opts, otlptracehttp.WithEndpointURL(strings.TrimSuffix(config.Endpoint, "/")+"/v1/traces"))
+	} else if config.Endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpoint(config.Endpoint))
+	}
+	if config.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	return opts
+}
+
+func httpLogOptions(config Config) []otlploghttp.Option {
+	var opts []otlploghttp.Option
+	if isURL(config.Endpoint) {
+		opts = append(opts, otlploghttp.WithEndpointURL(strings.TrimSuffix(config.Endpoint, "/")+"/v1/logs"))
+	} else if config.Endpoint != "" {
+		opts = append(opts, otlploghttp.WithEndpoint(config.Endpoint))
+	}
+	if config.Insecure {
+		opts = append(opts, otlploghttp.WithInsecure())
+	}
+	return opts
+}
@@ -0,0 +1,228 @@
+package telemetry
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/detector"
+
+	"go.opentelemetry.io/otel"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// collector stands in for an OTLP collector and keeps what it receives
+type collector struct {
+	mu    sync.Mutex
+	logs  []*logspb.ResourceLogs
+	spans []*tracepb.ResourceSpans
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var response proto.Message
+	switch r.URL.Path {
+	case "/v1/logs":
+		var req collogspb.ExportLogsServiceRequest
+		if err := proto.Unmarshal(body, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		c.addLogs(&req)
+		response = &collogspb.ExportLogsServiceResponse{}
+	case "/v1/traces":
+		var req coltracepb.ExportTraceServiceRequest
+		if err := proto.Unmarshal(body, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		c.addSpans(&req)
+		response = &coltracepb.ExportTraceServiceResponse{}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	data, _ := proto.Marshal(response)
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.Write(data)
+}
+
+func (c *collector) addLogs(req *collogspb.ExportLogsServiceRequest) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.logs = append(c.logs, req.ResourceLogs...)
+}
+
+func (c *collector) addSpans(req *coltracepb.ExportTraceServiceRequest) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.spans = append(c.spans, req.ResourceSpans...)
+}
+
+type grpcLogs struct {
+	collogspb.UnimplementedLogsServiceServer
+	*collector
+}
+
+func (g grpcLogs) Export(ctx context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	g.addLogs(req)
+	return &collogspb.ExportLogsServiceResponse{}, nil
+}
+
+type grpcTraces struct {
+	coltracepb.UnimplementedTraceServiceServer
+	*collector
+}
+
+func (g grpcTraces) Export(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	g.addSpans(req)
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+// failureRecord finds the log record of a reported failure and the pod its
+// resource names
+func (c *collector) failureRecord() (*logspb.LogRecord, string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, resourceLogs := range c.logs {
+		for _, scopeLogs := range resourceLogs.ScopeLogs {
+			for _, record := range scopeLogs.LogRecords {
+				if record.EventName == "pod_detective.failure" {
+					return record, stringAttribute(resourceLogs.Resource.GetAttributes(), "k8s.pod.name")
+				}
+			}
+		}
+	}
+	return nil, ""
+}
+
+func (c *collector) hasSpan(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, resourceSpans := range c.spans {
+		for _, scopeSpans := range resourceSpans.ScopeSpans {
+			for _, span := range scopeSpans.Spans {
+				if span.Name == name {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func stringAttribute(attrs []*commonpb.KeyValue, key string) string {
+	for _, attr := range attrs {
+		if attr.Key == key {
+			return attr.Value.GetStringValue()
+		}
+	}
+	return ""
+}
+
+func crashingPod() *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop", UID: "1b2c"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "app",
+				RestartCount: 3,
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+					Reason: "CrashLoopBackOff",
+				}},
+			}},
+		},
+	}
+}
+
+func TestExportToCollector(t *testing.T) {
+	tests := []struct {
+		protocol string
+		serve    func(t *testing.T, c *collector) string // returns the endpoint
+	}{
+		{"http", func(t *testing.T, c *collector) string {
+			server := httptest.NewServer(c)
+			t.Cleanup(server.Close)
+			return server.URL
+		}},
+		{"grpc", func(t *testing.T, c *collector) string {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			server := grpc.NewServer()
+			collogspb.RegisterLogsServiceServer(server, grpcLogs{collector: c})
+			coltracepb.RegisterTraceServiceServer(server, grpcTraces{collector: c})
+			go server.Serve(listener)
+			t.Cleanup(server.Stop)
+			return listener.Addr().String()
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.protocol, func(t *testing.T) {
+			c := &collector{}
+			ctx := context.Background()
+			provider, err := Setup(ctx, Config{Endpoint: tt.serve(t, c), Protocol: tt.protocol, Insecure: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			d := detector.New(fake.NewClientset(crashingPod()), detector.Options{
+				Output:   io.Discard,
+				Notifier: provider.LogNotifier(),
+			})
+			if err := d.Poll(ctx, "shop"); err != nil {
+				t.Fatal(err)
+			}
+
+			// The detector's own tracer stays bound to the first provider
+			// installed in the process, so check this one directly
+			_, span := otel.Tracer(loggerName).Start(ctx, "export-test")
+			span.End()
+			if err := provider.Shutdown(ctx); err != nil {
+				t.Fatalf("shutdown: %v", err)
+			}
+
+			record, pod := c.failureRecord()
+			if record == nil {
+				t.Fatal("collector received no failure log record")
+			}
+			if pod != "api" {
+				t.Errorf("k8s.pod.name = %q, want api", pod)
+			}
+			if reason := stringAttribute(record.Attributes, "pod_detective.reason"); reason != "CrashLoopBackOff" {
+				t.Errorf("pod_detective.reason = %q, want CrashLoopBackOff", reason)
+			}
+			if record.SeverityText != "ERROR" {
+				t.Errorf("severity = %q, want ERROR", record.SeverityText)
+			}
+			if !c.hasSpan("export-test") {
+				t.Error("collector received no span")
+			}
+		})
+	}
+}
@@ -0,0 +1,442 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/explainer"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/inspect"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/notifier"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true)
+	headerStyle   = lipgloss.NewStyle().Bold(true).Faint(true)
+	selectedStyle = lipgloss.NewStyle().Reverse(true)
+	resolvedStyle = lipgloss.NewStyle().Faint(true)
+	helpStyle     = lipgloss.NewStyle().Faint(true)
+	failureStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+)
+
+// incident is one row of the table
+type incident struct {
+	key         string
+	info        explainer.FailureInfo
+	explanation string
+	firstSeen   time.Time
+	resolvedAt  time.Time // zero while the failure lasts
+	recovery    *notifier.Recovery
+}
+
+func incidentKey(info explainer.FailureInfo) string {
+	return strings.Join([]string{info.Cluster, info.Namespace, info.Workload, info.PodName, info.ContainerName, info.Reason}, "/")
+}
+
+type model struct {
+	ctx       context.Context
+	clientset kubernetes.Interface
+	namespace string
+
+	incidents []*incident
+	restarts  map[string]int32
+
+	// List view
+	cursor          int
+	showResolved    bool
+	namespaceFilter string
+	reasonFilter    string
+
+	// Detail view, open when detail is set
+	detail   *incident
+	report   string
+	loading  bool
+	commands []explainer.DebugCommand
+	command  int
+	scroll   int
+
+	width, height int
+	status        string
+	err           error
+}
+
+func newModel(ctx context.Context, clientset kubernetes.Interface, namespace string) model {
+	return model{ctx: ctx, clientset: clientset, namespace: namespace, restarts: make(map[string]int32)}
+}
+
+func (m model) Init() tea.Cmd {
+	// Redraw every second so ages stay current
+	return tick()
+}
+
+type tickMsg struct{}
+
+func tick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg { return tickMsg{} })
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+
+	case tickMsg:
+		return m, tick()
+
+	case eventMsg:
+		m.applyEvent(notifier.Event(msg))
+
+	case restartsMsg:
+		m.restarts = msg
+
+	case reportMsg:
+		if m.detail != nil && m.detail.key == msg.key {
+			m.loading = false
+			if msg.err == nil {
+				m.report = msg.text
+			} else {
+				m.status = fmt.Sprintf("Showing the explanation only, the pod can't be inspected: %v", msg.err)
+			}
+		}
+
+	case commandDoneMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Command failed: %v", msg.err)
+		}
+
+	case errMsg:
+		m.err = msg.err
+		return m, tea.Quit
+
+	case tea.KeyMsg:
+		m.status = ""
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		if m.detail != nil {
+			return m.updateDetail(msg)
+		}
+		return m.updateList(msg)
+	}
+	return m, nil
+}
+
+func (m *model) applyEvent(event notifier.Event) {
+	key := incidentKey(event.Failure)
+	index := slices.IndexFunc(m.incidents, func(inc *incident) bool { return inc.key == key })
+
+	switch event.Type {
+	case notifier.EventFailure:
+		inc := &incident{key: key, info: event.Failure, explanation: event.Diagnosis, firstSeen: event.Time}
+		if index >= 0 {
+			m.incidents[index] = inc
+		} else {
+			m.incidents = append(m.incidents, inc)
+		}
+	case notifier.EventRecovery:
+		if index >= 0 {
+			m.incidents[index].resolvedAt = event.Time
+			m.incidents[index].recovery = event.Recovery
+		}
+	}
+}
+
+// visible returns the rows to show: active incidents first, newest first
+func (m model) visible() []*incident {
+	var rows []*incident
+	for _, inc := range m.incidents {
+		if !inc.resolvedAt.IsZero() && !m.showResolved {
+			continue
+		}
+		if m.namespaceFilter != "" && inc.info.Namespace != m.namespaceFilter {
+			continue
+		}
+		if m.reasonFilter != "" && inc.info.Reason != m.reasonFilter {
+			continue
+		}
+		rows = append(rows, inc)
+	}
+
+	slices.SortStableFunc(rows, func(a, b *incident) int {
+		if activeA, activeB := a.resolvedAt.IsZero(), b.resolvedAt.IsZero(); activeA != activeB {
+			if activeA {
+				return -1
+			}
+			return 1
+		}
+		return b.firstSeen.Compare(a.firstSeen)
+	})
+	return rows
+}
+
+func (m model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	rows := m.visible()
+
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "up", "k":
+		m.cursor = max(m.cursor-1, 0)
+	case "down", "j":
+		m.cursor = min(m.cursor+1, max(len(rows)-1, 0))
+	case "enter":
+		if m.cursor < len(rows) {
+			return m.open(rows[m.cursor])
+		}
+	case "n":
+		m.namespaceFilter = next(m.values(func(info explainer.FailureInfo) string { return info.Namespace }), m.namespaceFilter)
+		m.cursor = 0
+	case "r":
+		m.reasonFilter = next(m.values(func(info explainer.FailureInfo) string { return info.Reason }), m.reasonFilter)
+		m.cursor = 0
+	case "a":
+		m.showResolved = !m.showResolved
+		m.cursor = 0
+	case "esc":
+		m.namespaceFilter, m.reasonFilter = "", ""
+		m.cursor = 0
+	}
+	return m, nil
+}
+
+// values lists the distinct values of a field across incidents, sorted
+func (m model) values(field func(explainer.FailureInfo) string) []string {
+	var values []string
+	for _, inc := range m.incidents {
+		if value := field(inc.info); !slices.Contains(values, value) {
+			values = append(values, value)
+		}
+	}
+	slices.Sort(values)
+	return values
+}
+
+// next cycles a filter through "" (off) and every value
+func next(values []string, current string) string {
+	index := slices.Index(values, current)
+	if index+1 < len(values) {
+		return values[index+1]
+	}
+	return ""
+}
+
+// open shows an incident's explanation and starts inspecting its pod
+func (m model) open(inc *incident) (tea.Model, tea.Cmd) {
+	m.detail = inc
+	m.report = inc.explanation
+	m.commands = explainer.DebugCommands(inc.info)
+	m.command, m.scroll = 0, 0
+
+	// Node-level incidents have no pod to inspect
+	m.loading = inc.info.PodName != ""
+	if !m.loading {
+		return m, nil
+	}
+
+	ctx, clientset, info := m.ctx, m.clientset, inc.info
+	return m, func() tea.Msg {
+		report, err := inspect.Pod(ctx, clientset, info.Namespace, info.PodName, inspect.Options{})
+		if err != nil {
+			return reportMsg{key: inc.key, err: err}
+		}
+		var text bytes.Buffer
+		report.Write(&text)
+		return reportMsg{key: inc.key, text: text.String()}
+	}
+}
+
+func (m model) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key := msg.String(); key {
+	case "q", "esc", "backspace":
+		m.detail = nil
+	case "up", "k":
+		m.scroll = max(m.scroll-1, 0)
+	case "down", "j":
+		m.scroll++
+	case "pgup":
+		m.scroll = max(m.scroll-m.bodyHeight(), 0)
+	case "pgdown", " ":
+		m.scroll += m.bodyHeight()
+	case "tab":
+		if len(m.commands) > 0 {
+			m.command = (m.command + 1) % len(m.commands)
+		}
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		if index := int(key[0] - '1'); index < len(m.commands) {
+			m.command = index
+		}
+	case "c":
+		if m.command < len(m.commands) {
+			// OSC 52 works over SSH and in most terminals
+			termenv.Copy(m.commands[m.command].Command)
+			m.status = "Copied to the clipboard"
+		}
+	case "x":
+		if m.command < len(m.commands) {
+			command := m.commands[m.command].Command
+			script := command + "; echo; printf 'Press Enter to return to the dashboard '; read _"
+			return m, tea.ExecProcess(exec.Command("sh", "-c", script), func(err error) tea.Msg {
+				return commandDoneMsg{err}
+			})
+		}
+	}
+	return m, nil
+}
+
+// bodyHeight is how many report lines fit under the commands
+func (m model) bodyHeight() int {
+	return max(m.height-len(m.commands)-6, 3)
+}
+
+func (m model) View() string {
+	if m.detail != nil {
+		return m.viewDetail()
+	}
+	return m.viewList()
+}
+
+func (m model) viewList() string {
+	var b strings.Builder
+
+	scope := "namespace " + m.namespace
+	if m.namespace == "" {
+		scope = "all namespaces"
+	}
+	active, resolved := 0, 0
+	for _, inc := range m.incidents {
+		if inc.resolvedAt.IsZero() {
+			active++
+		} else {
+			resolved++
+		}
+	}
+	b.WriteString(titleStyle.Render(fmt.Sprintf("🔍 Pod Detective — %s — %d failing, %d resolved", scope, active, resolved)))
+	var filters []string
+	if m.namespaceFilter != "" {
+		filters = append(filters, "namespace="+m.namespaceFilter)
+	}
+	if m.reasonFilter != "" {
+		filters = append(filters, "reason="+m.reasonFilter)
+	}
+	if len(filters) > 0 {
+		b.WriteString("  [" + strings.Join(filters, " ") + "]")
+	}
+	b.WriteString("\n\n")
+
+	rows := m.visible()
+	table := [][]string{{"NAMESPACE", "WORKLOAD", "POD", "CONTAINER", "REASON", "RESTARTS", "AGE"}}
+	for _, inc := range rows {
+		info := inc.info
+		workload := info.Workload
+		if workload == "" {
+			workload = "-"
+		}
+		restarts := "-"
+		if count, ok := m.restarts[info.Namespace+"/"+info.PodName+"/"+info.ContainerName]; ok {
+			restarts = fmt.Sprint(count)
+		}
+		age := duration.HumanDuration(time.Since(inc.firstSeen))
+		if !inc.resolvedAt.IsZero() {
+			age = "resolved " + duration.HumanDuration(time.Since(inc.resolvedAt)) + " ago"
+			if inc.recovery != nil {
+				age += ", after " + inc.recovery.Duration().String()
+			}
+		}
+		table = append(table, []string{info.Namespace, workload, info.PodName, info.ContainerName, info.Reason, restarts, age})
+	}
+
+	widths := make([]int, len(table[0]))
+	for _, row := range table {
+		for i, cell := range row {
+			widths[i] = max(widths[i], lipgloss.Width(cell))
+		}
+	}
+
+	for i, row := range table {
+		cells := make([]string, len(row))
+		for j, cell := range row {
+			cells[j] = cell + strings.Repeat(" ", widths[j]-lipgloss.Width(cell))
+		}
+		line := strings.Join(cells, "  ")
+		if m.width > 0 && lipgloss.Width(line) > m.width {
+			line = line[:m.width]
+		}
+
+		switch {
+		case i == 0:
+			line = headerStyle.Render(line)
+		case i-1 == m.cursor:
+			line = selectedStyle.Render(line)
+		case !rows[i-1].resolvedAt.IsZero():
+			line = resolvedStyle.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+
+	if len(rows) == 0 {
+		b.WriteString("\n✅ No failing pods")
+		if len(filters) > 0 || (resolved > 0 && !m.showResolved) {
+			b.WriteString(" match (esc clears filters, a shows resolved)")
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n" + m.statusLine())
+	b.WriteString(helpStyle.Render("↑/↓ select • enter details • n namespace • r reason • esc clear filters • a toggle resolved • q quit"))
+	return b.String()
+}
+
+func (m model) viewDetail() string {
+	var b strings.Builder
+	info := m.detail.info
+
+	title := fmt.Sprintf("🔍 %s/%s — container %s — %s", info.Namespace, info.PodName, info.ContainerName, info.Reason)
+	if info.PodName == "" {
+		title = fmt.Sprintf("🔍 %s — %s", info.Workload, info.Reason)
+	}
+	b.WriteString(titleStyle.Render(title))
+	if m.loading {
+		b.WriteString("  (inspecting pod…)")
+	}
+	b.WriteString("\n\n🐛 DEBUG COMMANDS:\n")
+	for i, command := range m.commands {
+		line := fmt.Sprintf("%d. %s", i+1, command.Command)
+		if i == m.command {
+			line = selectedStyle.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+	b.WriteString("\n")
+
+	lines := strings.Split(strings.TrimRight(m.report, "\n"), "\n")
+	height := m.bodyHeight()
+	scroll := min(m.scroll, max(len(lines)-height, 0))
+	end := min(scroll+height, len(lines))
+	for _, line := range lines[scroll:end] {
+		if strings.HasPrefix(line, "❌") {
+			line = failureStyle.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+
+	b.WriteString("\n" + m.statusLine())
+	b.WriteString(helpStyle.Render(fmt.Sprintf("↑/↓ pgup/pgdn scroll (%d/%d) • tab/1-9 pick command • c copy • x run • esc back", end, len(lines))))
+	return b.String()
+}
+
+func (m model) statusLine() string {
+	if m.status == "" {
+		return ""
+	}
+	return m.status + "\n"
+}
@@ -0,0 +1,82 @@
+// Package tui is an interactive terminal dashboard of failing pods
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/detector"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/notifier"
+
+	tea "github.com/charmbracelet/bubbletea"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Run shows the dashboard for namespace ("" for all) until the user quits.
+// The detector is configured by opts; its output is taken over by the
+// dashboard.
+func Run(ctx context.Context, clientset kubernetes.Interface, namespace string, opts detector.Options) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	program := tea.NewProgram(newModel(ctx, clientset, namespace), tea.WithAltScreen(), tea.WithContext(ctx))
+
+	opts.Output = io.Discard
+	opts.Observer = sink{program: program}
+	podDetector := detector.New(clientset, opts)
+
+	go func() {
+		if err := podDetector.WatchPods(ctx, namespace); err != nil {
+			program.Send(errMsg{err})
+		}
+	}()
+
+	final, err := program.Run()
+	if err != nil && !errors.Is(err, tea.ErrProgramKilled) {
+		return fmt.Errorf("failed to run dashboard: %w", err)
+	}
+	if m, ok := final.(model); ok {
+		return m.err
+	}
+	return nil
+}
+
+// Messages from the detector and background commands
+type (
+	eventMsg    notifier.Event
+	restartsMsg map[string]int32 // "namespace/pod/container" → restarts
+	errMsg      struct{ err error }
+	reportMsg   struct {
+		key  string
+		text string
+		err  error
+	}
+	commandDoneMsg struct{ err error }
+)
+
+// sink forwards what the detector observes to the dashboard
+type sink struct {
+	program *tea.Program
+}
+
+func (s sink) ObservePods(namespace string, pods *corev1.PodList) {
+	restarts := make(restartsMsg)
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			restarts[pod.Namespace+"/"+pod.Name+"/"+status.Name] = status.RestartCount
+		}
+	}
+	s.program.Send(restarts)
+}
+
+func (s sink) ObserveLog(namespace, podName, containerName, log string, err error) {}
+
+func (s sink) ObserveObject(object runtime.Object) {}
+
+func (s sink) ObserveEvent(event notifier.Event) {
+	s.program.Send(eventMsg(event))
+}
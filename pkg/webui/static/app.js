@@ -0,0 +1,218 @@
+// Pod Detective web UI. Incidents arrive over Server-Sent Events; everything
+// from the cluster is inserted as text, never as HTML.
+const PodDetective = (() => {
+  const $ = (id) => document.getElementById(id);
+
+  function el(tag, text, className) {
+    const node = document.createElement(tag);
+    if (text !== undefined) node.textContent = text;
+    if (className) node.className = className;
+    return node;
+  }
+
+  function age(since) {
+    const seconds = Math.max(0, Math.round((Date.now() - new Date(since)) / 1000));
+    if (seconds < 60) return seconds + "s";
+    if (seconds < 3600) return Math.floor(seconds / 60) + "m";
+    if (seconds < 86400) return Math.floor(seconds / 3600) + "h" + Math.floor((seconds % 3600) / 60) + "m";
+    return Math.floor(seconds / 86400) + "d";
+  }
+
+  // stream calls onReady on every (re)connection and onIncident per update
+  function stream(onReady, onIncident) {
+    const source = new EventSource("/api/stream");
+    const connection = $("connection");
+    source.addEventListener("ready", () => {
+      connection.textContent = "● live";
+      connection.classList.add("live");
+      onReady();
+    });
+    source.addEventListener("incident", (e) => onIncident(JSON.parse(e.data)));
+    source.onerror = () => {
+      connection.textContent = "reconnecting…";
+      connection.classList.remove("live");
+    };
+  }
+
+  async function fetchJSON(url) {
+    const response = await fetch(url);
+    if (!response.ok) throw new Error(await response.text());
+    return response.json();
+  }
+
+  // ===== INCIDENT LIST =====
+
+  function list() {
+    const incidents = new Map();
+    const fresh = new Set();
+    const params = new URLSearchParams(location.search);
+    const filters = { namespace: $("namespace"), reason: $("reason") };
+    const showResolved = $("resolved");
+    showResolved.checked = params.get("resolved") !== "false";
+
+    function fillSelect(select, values) {
+      const current = select.value || params.get(select.id) || "";
+      select.replaceChildren(el("option", "all"));
+      select.firstChild.value = "";
+      for (const value of [...values].sort()) select.append(el("option", value));
+      select.value = values.has(current) ? current : "";
+    }
+
+    function render() {
+      const all = [...incidents.values()];
+      fillSelect(filters.namespace, new Set(all.map((i) => i.failure.namespace)));
+      fillSelect(filters.reason, new Set(all.map((i) => i.failure.reason)));
+
+      const rows = all
+        .filter((i) => showResolved.checked || !i.resolvedAt)
+        .filter((i) => !filters.namespace.value || i.failure.namespace === filters.namespace.value)
+        .filter((i) => !filters.reason.value || i.failure.reason === filters.reason.value)
+        // Active first, newest first
+        .sort((a, b) => (!!a.resolvedAt - !!b.resolvedAt) || (new Date(b.firstSeen) - new Date(a.firstSeen)));
+
+      const body = $("incidents");
+      body.replaceChildren();
+      for (const incident of rows) {
+        const f = incident.failure;
+        const row = el("tr", undefined, "incident");
+        if (incident.resolvedAt) row.classList.add("resolved");
+        if (fresh.has(incident.id)) row.classList.add("new");
+        row.append(
+          el("td", incident.severity, "severity " + incident.severity),
+          el("td", (f.cluster ? f.cluster + "/" : "") + f.namespace),
+          el("td", f.workload || "-"),
+          el("td", f.podName),
+          el("td", f.containerName),
+          el("td", f.reason),
+          el("td", age(incident.firstSeen)),
+          el("td", incident.resolvedAt ? "✅ resolved " + age(incident.resolvedAt) + " ago" : "❌ failing"),
+        );
+        row.title = incident.summary + (incident.recovery ? ", " + incident.recovery : "");
+        row.onclick = () => { location.href = "/incidents/" + incident.id; };
+        body.append(row);
+      }
+      fresh.clear();
+
+      const active = all.filter((i) => !i.resolvedAt).length;
+      $("counts").textContent = active + " failing, " + (all.length - active) + " resolved";
+      $("empty").hidden = rows.length > 0;
+    }
+
+    function saveFilters() {
+      const query = new URLSearchParams();
+      for (const [name, select] of Object.entries(filters)) {
+        if (select.value) query.set(name, select.value);
+      }
+      if (!showResolved.checked) query.set("resolved", "false");
+      history.replaceState(null, "", query.size ? "?" + query : location.pathname);
+      render();
+    }
+
+    filters.namespace.onchange = saveFilters;
+    filters.reason.onchange = saveFilters;
+    showResolved.onchange = saveFilters;
+
+    stream(async () => {
+      incidents.clear();
+      for (const incident of await fetchJSON("/api/incidents")) incidents.set(incident.id, incident);
+      render();
+    }, (incident) => {
+      if (!incidents.has(incident.id)) fresh.add(incident.id);
+      incidents.set(incident.id, incident);
+      render();
+    });
+
+    // Keep ages current
+    setInterval(render, 5000);
+  }
+
+  // ===== INCIDENT PAGE =====
+
+  async function incident() {
+    const id = location.pathname.split("/").pop();
+    let detail;
+    try {
+      detail = await fetchJSON("/api/incidents/" + id);
+    } catch (err) {
+      $("title").textContent = "Incident not found";
+      $("status").textContent = "It may have been resolved long enough ago to be forgotten.";
+      return;
+    }
+
+    const f = detail.failure;
+    const subject = f.podName ? f.namespace + "/" + f.podName + " — " + f.containerName : f.workload;
+    document.title = (f.podName || f.workload) + " — Pod Detective";
+    $("title").textContent = (f.cluster ? "[" + f.cluster + "] " : "") + subject;
+    $("summary").textContent = detail.diagnosis.summary;
+    $("explanation").textContent = detail.explanation;
+
+    function renderStatus() {
+      const since = "first seen " + new Date(detail.firstSeen).toLocaleString();
+      $("status").textContent = detail.resolvedAt
+        ? "✅ Resolved " + age(detail.resolvedAt) + " ago (" + since + (detail.recovery ? ", " + detail.recovery : "") + ")"
+        : "❌ " + f.reason + " for " + age(detail.firstSeen) + " (" + since + ")";
+    }
+    renderStatus();
+
+    if (detail.problems) {
+      const problems = $("problems");
+      problems.hidden = false;
+      problems.append(el("strong", "Some details could not be loaded:"));
+      const items = el("ul");
+      for (const problem of detail.problems) items.append(el("li", problem));
+      problems.append(items);
+    }
+
+    for (const line of detail.diagnosis.evidence || []) $("evidence").append(el("li", line));
+    for (const fix of detail.diagnosis.fixes || []) $("fixes").append(el("li", fix));
+
+    for (const command of detail.diagnosis.commands || []) {
+      const item = el("li");
+      if (command.description) item.append(el("div", command.description, "muted"));
+      const code = el("code", command.command);
+      const copy = el("button", "Copy");
+      copy.onclick = async () => {
+        await navigator.clipboard.writeText(command.command);
+        copy.textContent = "Copied";
+        setTimeout(() => { copy.textContent = "Copy"; }, 1500);
+      };
+      item.append(code, copy);
+      $("commands").append(item);
+    }
+
+    for (const event of detail.events || []) {
+      const row = el("tr");
+      row.append(
+        el("td", event.type),
+        el("td", event.reason),
+        el("td", event.count || ""),
+        el("td", event.lastSeen ? age(event.lastSeen) + " ago" : ""),
+        el("td", event.message),
+      );
+      $("events").append(row);
+    }
+    if (!detail.events) {
+      const row = el("tr");
+      row.append(el("td", "No events", "muted"));
+      $("events").append(row);
+    }
+
+    for (const log of detail.logs || []) {
+      $("logs").append(el("h4", log.container + (log.previous ? " (previous instance)" : "")));
+      $("logs").append(el("pre", log.error ? "Could not fetch log: " + log.error : log.log || "(empty)"));
+    }
+    if (!detail.logs) $("logs").append(el("p", "No logs", "muted"));
+
+    // Follow this incident's resolution live
+    stream(() => {}, (update) => {
+      if (update.id === detail.id) {
+        detail.resolvedAt = update.resolvedAt;
+        detail.recovery = update.recovery;
+        renderStatus();
+      }
+    });
+    setInterval(renderStatus, 5000);
+  }
+
+  return { list, incident };
+})();
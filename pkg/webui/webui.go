@@ -0,0 +1,332 @@
+// Package webui serves a browser dashboard of current and recent incidents,
+// updated live over Server-Sent Events
+package webui
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/explainer"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/inspect"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/notifier"
+
+	"k8s.io/client-go/kubernetes"
+)
+
+//go:embed static
+var static embed.FS
+
+// maxResolved is how many resolved incidents are kept for the "recent" list
+const maxResolved = 200
+
+// Incident is one failure as shown in the UI
+type Incident struct {
+	ID         int                   `json:"id"`
+	Failure    explainer.FailureInfo `json:"failure"`
+	Severity   string                `json:"severity"`
+	Summary    string                `json:"summary"`
+	FirstSeen  time.Time             `json:"firstSeen"`
+	ResolvedAt *time.Time            `json:"resolvedAt,omitempty"`
+	Recovery   string                `json:"recovery,omitempty"` // e.g. "recovered after 4m12s (3 restarts)"
+
+	explanation string
+}
+
+// Detail is an incident with its explanation and what the cluster currently
+// says about the pod
+type Detail struct {
+	Incident
+	Diagnosis   explainer.Diagnosis `json:"diagnosis"`
+	Explanation string              `json:"explanation"`
+	Events      []Event             `json:"events,omitempty"`
+	Logs        []Log               `json:"logs,omitempty"`
+
+	// Problems lists what could not be looked up, e.g. because the pod is gone
+	Problems []string `json:"problems,omitempty"`
+}
+
+type Event struct {
+	Type     string    `json:"type"`
+	Reason   string    `json:"reason"`
+	Message  string    `json:"message"`
+	Count    int32     `json:"count,omitempty"`
+	LastSeen time.Time `json:"lastSeen,omitzero"`
+}
+
+type Log struct {
+	Container string `json:"container"`
+	Previous  bool   `json:"previous,omitempty"`
+	Log       string `json:"log,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Dashboard keeps the incidents the detector reports and serves them. It
+// implements notifier.Notifier.
+type Dashboard struct {
+	mu          sync.Mutex
+	nextID      int
+	incidents   []*Incident // oldest first
+	clusters    map[string]kubernetes.Interface
+	subscribers map[chan []byte]struct{}
+}
+
+func New() *Dashboard {
+	return &Dashboard{
+		nextID:      1,
+		clusters:    make(map[string]kubernetes.Interface),
+		subscribers: make(map[chan []byte]struct{}),
+	}
+}
+
+// AddCluster lets detail pages look up pods of a cluster ("" for the only one)
+func (d *Dashboard) AddCluster(name string, clientset kubernetes.Interface) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.clusters[name] = clientset
+}
+
+func sameFailure(a, b explainer.FailureInfo) bool {
+	return a.Cluster == b.Cluster && a.Namespace == b.Namespace && a.PodName == b.PodName &&
+		a.ContainerName == b.ContainerName && a.Reason == b.Reason && a.Workload == b.Workload
+}
+
+func (d *Dashboard) Notify(ctx context.Context, event notifier.Event) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var changed *Incident
+	switch event.Type {
+	case notifier.EventFailure:
+		changed = &Incident{
+			ID:          d.nextID,
+			Failure:     event.Failure,
+			Severity:    explainer.SeverityOf(event.Failure).String(),
+			Summary:     explainer.Summary(event.Failure),
+			FirstSeen:   event.Time,
+			explanation: event.Diagnosis,
+		}
+		d.nextID++
+		d.incidents = append(d.incidents, changed)
+
+	case notifier.EventRecovery:
+		for _, inc := range slices.Backward(d.incidents) {
+			if inc.ResolvedAt == nil && sameFailure(inc.Failure, event.Failure) {
+				resolvedAt := event.Time
+				inc.ResolvedAt = &resolvedAt
+				if event.Recovery != nil {
+					inc.Recovery = event.Recovery.String()
+				}
+				changed = inc
+				break
+			}
+		}
+		if changed == nil {
+			return nil
+		}
+		d.prune()
+	}
+
+	data, err := json.Marshal(changed)
+	if err != nil {
+		return err
+	}
+	for subscriber := range d.subscribers {
+		select {
+		case subscriber <- data:
+		default:
+			// A browser that can't keep up reloads the list on reconnect
+			slog.Debug("Dropping UI update for a slow client")
+		}
+	}
+	return nil
+}
+
+// prune forgets the oldest resolved incidents beyond maxResolved
+func (d *Dashboard) prune() {
+	resolved := 0
+	for _, inc := range d.incidents {
+		if inc.ResolvedAt != nil {
+			resolved++
+		}
+	}
+	d.incidents = slices.DeleteFunc(d.incidents, func(inc *Incident) bool {
+		if inc.ResolvedAt != nil && resolved > maxResolved {
+			resolved--
+			return true
+		}
+		return false
+	})
+}
+
+func (d *Dashboard) list() []Incident {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	list := make([]Incident, len(d.incidents))
+	for i, inc := range d.incidents {
+		list[i] = *inc
+	}
+	return list
+}
+
+func (d *Dashboard) find(id int) (Incident, kubernetes.Interface, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, inc := range d.incidents {
+		if inc.ID == id {
+			return *inc, d.clusters[inc.Failure.Cluster], true
+		}
+	}
+	return Incident{}, nil, false
+}
+
+// Handler serves the UI and its API:
+//
+//	GET /                    incident list
+//	GET /incidents/{id}      incident page
+//	GET /api/incidents       all incidents as JSON
+//	GET /api/incidents/{id}  one incident with explanation, events and logs
+//	GET /api/stream          Server-Sent Events, one "incident" event per change
+func (d *Dashboard) Handler() http.Handler {
+	assets, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err)
+	}
+	files := http.FileServerFS(assets)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFileFS(w, r, assets, "index.html")
+	})
+	mux.HandleFunc("GET /incidents/{id}", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFileFS(w, r, assets, "incident.html")
+	})
+	mux.Handle("GET /static/", http.StripPrefix("/static/", files))
+	mux.HandleFunc("GET /api/incidents", d.serveList)
+	mux.HandleFunc("GET /api/incidents/{id}", d.serveDetail)
+	mux.HandleFunc("GET /api/stream", d.serveStream)
+	return mux
+}
+
+func (d *Dashboard) serveList(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, d.list())
+}
+
+func (d *Dashboard) serveDetail(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid incident id", http.StatusBadRequest)
+		return
+	}
+	inc, clientset, ok := d.find(id)
+	if !ok {
+		http.Error(w, "incident not found", http.StatusNotFound)
+		return
+	}
+
+	detail := Detail{
+		Incident:    inc,
+		Diagnosis:   explainer.Diagnose(inc.Failure),
+		Explanation: inc.explanation,
+	}
+
+	// Node-level incidents have no pod to inspect
+	if inc.Failure.PodName == "" {
+		writeJSON(w, detail)
+		return
+	}
+	if clientset == nil {
+		detail.Problems = append(detail.Problems, "no client for this cluster")
+		writeJSON(w, detail)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+	report, err := inspect.Pod(ctx, clientset, inc.Failure.Namespace, inc.Failure.PodName, inspect.Options{LogLines: 50})
+	if err != nil {
+		detail.Problems = append(detail.Problems, err.Error())
+		writeJSON(w, detail)
+		return
+	}
+
+	for _, event := range report.Events {
+		detail.Events = append(detail.Events, Event{
+			Type:     event.Type,
+			Reason:   event.Reason,
+			Message:  strings.TrimSpace(event.Message),
+			Count:    event.Count,
+			LastSeen: event.LastTimestamp.Time,
+		})
+	}
+	for _, log := range report.Logs {
+		entry := Log{Container: log.Container, Previous: log.Previous, Log: log.Log}
+		if log.Err != nil {
+			entry.Error = log.Err.Error()
+		}
+		detail.Logs = append(detail.Logs, entry)
+	}
+	detail.Problems = append(detail.Problems, report.Problems...)
+	writeJSON(w, detail)
+}
+
+func (d *Dashboard) serveStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	updates := make(chan []byte, 64)
+	d.mu.Lock()
+	d.subscribers[updates] = struct{}{}
+	d.mu.Unlock()
+	defer func() {
+		d.mu.Lock()
+		delete(d.subscribers, updates)
+		d.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	// Tell the browser to fetch the list once subscribed, so no update falls
+	// between the two
+	fmt.Fprint(w, "retry: 3000\nevent: ready\ndata: {}\n\n")
+	flusher.Flush()
+
+	// Comments keep proxies from closing an idle stream
+	keepalive := time.NewTicker(30 * time.Second)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case data := <-updates:
+			fmt.Fprintf(w, "event: incident\ndata: %s\n\n", data)
+		case <-keepalive.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+		}
+		flusher.Flush()
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Debug("Failed to write UI response", "error", err)
+	}
+}
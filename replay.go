@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/recording"
+)
+
+// runReplay feeds a recording made with 'watch --record' back through the
+// detector, to reproduce its output after the fact
+func runReplay(args []string) {
+	fs := newFlagSet("replay", "replay FILE [flags]")
+	speed := fs.Float64("speed", 1, "replay pace: 1 keeps the recorded timing, 10 is ten times faster, 0 is as fast as possible")
+	check := fs.Bool("check", false, "exit 1 if the replay reports different failures or recoveries than were recorded")
+	var logs logFlags
+	logs.register(fs)
+
+	positional := parseInterspersed(fs, args)
+	if len(positional) != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	logs.setup()
+
+	reader, err := recording.Open(positional[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening recording: %v\n", err)
+		os.Exit(1)
+	}
+	defer reader.Close()
+
+	fmt.Printf("⏪ Replaying %s (namespace %s, polled every %s)\n\n", positional[0], reader.Header.Namespace, reader.Header.Interval)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	result, err := recording.Replay(ctx, reader, recording.ReplayOptions{Speed: *speed})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error replaying: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("⏹️  Replayed %d polls: %d failures and recoveries recorded, %d reported on replay\n",
+		result.Polls, result.Recorded, result.Replayed)
+	for _, mismatch := range result.Mismatches {
+		fmt.Printf("❌ %s\n", mismatch)
+	}
+	if *check && len(result.Mismatches) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/detector"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/explainer"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// exitFailuresFound is the scan exit code when failures at or above --fail-on
+// are found, kept apart from 1 (the scan itself failed) and 2 (bad flags)
+const exitFailuresFound = 3
+
+// runScan explains every failing pod once, prints a summary and exits
+// non-zero when failures reach the --fail-on severity
+func runScan(args []string) {
+	fs := newFlagSet("scan", "scan [-n NS[,NS...] | -A] [flags]")
+	var kube kubeFlags
+	kube.register(fs)
+	var logs logFlags
+	logs.register(fs)
+	labelSelector := fs.String("labels", "", "(optional) label selector (e.g., 'app=nginx,tier=frontend')")
+	allNamespaces := fs.Bool("all-namespaces", false, "scan every namespace")
+	fs.BoolVar(allNamespaces, "A", false, "shorthand for --all-namespaces")
+	failOn := fs.String("fail-on", "warning", "exit 3 when a failure of this severity or higher is found: info, warning, critical or none")
+	wait := fs.Duration("wait", 0, "(optional) wait up to this long for pods to become ready before scanning (e.g., '2m')")
+	quiet := fs.Bool("quiet", false, "print only the summary table, not the explanations")
+	fs.Parse(args)
+
+	threshold, err := parseFailOn(*failOn)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --fail-on: %v\n", err)
+		os.Exit(2)
+	}
+
+	logs.setup()
+
+	// -n takes a comma-separated list to scan several namespaces
+	namespaces := strings.Split(resolveNamespace(kube), ",")
+	if *allNamespaces {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+
+	podDetector := detector.New(newClientset(kube), detector.Options{LabelSelector: *labelSelector})
+	ctx := context.Background()
+
+	if *wait > 0 {
+		waitForPods(ctx, podDetector, namespaces, *wait)
+	}
+
+	var failures []explainer.FailureInfo
+	for _, namespace := range namespaces {
+		found, err := podDetector.Scan(ctx, namespace)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error scanning pods: %v\n", err)
+			os.Exit(1)
+		}
+		failures = append(failures, found...)
+	}
+
+	// Worst first
+	slices.SortStableFunc(failures, func(a, b explainer.FailureInfo) int {
+		return cmp.Or(
+			cmp.Compare(explainer.SeverityOf(b), explainer.SeverityOf(a)),
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.PodName, b.PodName),
+			cmp.Compare(a.ContainerName, b.ContainerName),
+		)
+	})
+
+	if len(failures) == 0 {
+		fmt.Printf("✅ No failing pods in %s\n", describeNamespaces(namespaces))
+		return
+	}
+
+	if !*quiet {
+		printExplanations(os.Stdout, failures)
+	}
+	printSummary(os.Stdout, failures)
+
+	blocking := 0
+	for _, info := range failures {
+		if threshold >= 0 && explainer.SeverityOf(info) >= threshold {
+			blocking++
+		}
+	}
+
+	fmt.Println()
+	if blocking > 0 {
+		fmt.Printf("❌ %d of %d failures are %s or worse\n", blocking, len(failures), threshold)
+		os.Exit(exitFailuresFound)
+	}
+	fmt.Printf("⚠️  %d failures found, none at the --fail-on threshold\n", len(failures))
+}
+
+// parseFailOn reads --fail-on, where "none" is a negative threshold no failure
+// reaches
+func parseFailOn(value string) (explainer.Severity, error) {
+	if value == "none" {
+		return -1, nil
+	}
+	return explainer.ParseSeverity(value)
+}
+
+// waitForPods polls until every pod is ready or the wait runs out, so pods
+// that are still starting after a deploy aren't reported as failures
+func waitForPods(ctx context.Context, podDetector *detector.PodDetector, namespaces []string, wait time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	fmt.Printf("⏳ Waiting up to %s for pods in %s to become ready...\n\n", wait, describeNamespaces(namespaces))
+	for {
+		settled := true
+		for _, namespace := range namespaces {
+			ok, err := podDetector.Settled(ctx, namespace)
+			if err != nil {
+				slog.Debug("Failed to check pods, still waiting", "namespace", namespace, "error", err)
+			}
+			settled = settled && ok && err == nil
+		}
+		if settled {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func describeNamespaces(namespaces []string) string {
+	if len(namespaces) == 1 && namespaces[0] == metav1.NamespaceAll {
+		return "all namespaces"
+	}
+	if len(namespaces) == 1 {
+		return "namespace " + namespaces[0]
+	}
+	return "namespaces " + strings.Join(namespaces, ", ")
+}
+
+// printSummary prints one table row per failure
+func printSummary(w io.Writer, failures []explainer.FailureInfo) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SEVERITY\tNAMESPACE\tPOD\tCONTAINER\tREASON\tEXIT\tWORKLOAD")
+	for _, info := range failures {
+		exitCode := "-"
+		if info.ExitCode != 0 {
+			exitCode = fmt.Sprint(info.ExitCode)
+		}
+		workload := info.Workload
+		if workload == "" {
+			workload = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", explainer.SeverityOf(info),
+			info.Namespace, info.PodName, info.ContainerName, info.Reason, exitCode, workload)
+	}
+	tw.Flush()
+}
+
+// printExplanations prints failures the same way watch does
+func printExplanations(w io.Writer, failures []explainer.FailureInfo) {
+	for _, info := range failures {
+		fmt.Fprintln(w, explainer.Explain(info))
+		fmt.Fprintln(w, "=====================================")
+		fmt.Fprintln(w)
+	}
+}
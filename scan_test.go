@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/explainer"
+)
+
+func TestParseFailOn(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    explainer.Severity
+		wantErr bool
+	}{
+		{"none", -1, false},
+		{"warning", explainer.SeverityWarning, false},
+		{"Critical", explainer.SeverityCritical, false},
+		{"loud", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseFailOn(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseFailOn(%q) = %v, %v; want %v, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// A bad --fail-on is a bad flag, so it exits 2 before touching the cluster
+func TestScanExitsTwoOnBadFailOn(t *testing.T) {
+	if os.Getenv("SCAN_BAD_FAIL_ON") == "1" {
+		runScan([]string{"--fail-on", "loud"})
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestScanExitsTwoOnBadFailOn$")
+	cmd.Env = append(os.Environ(), "SCAN_BAD_FAIL_ON=1")
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 2 {
+		t.Fatalf("scan --fail-on loud: %v, want exit status 2", err)
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/history"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/notifier"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/recording"
+)
+
+// runSummary aggregates the incidents in recordings made with 'watch --record'
+func runSummary(args []string) {
+	fs := newFlagSet("summary", "summary FILE... [flags]")
+	window := fs.Duration("window", 0, "(optional) summarize only this long before the end of the recordings (e.g., '24h'; default: everything)")
+	format := fs.String("format", "text", "output format: text or json")
+	var logs logFlags
+	logs.register(fs)
+
+	files := parseInterspersed(fs, args)
+	if len(files) == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q, expected text or json\n", *format)
+		os.Exit(1)
+	}
+	logs.setup()
+
+	incidents, first, last, err := loadIncidents(files)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// The window ends with the recordings, so yesterday's file summarizes
+	// yesterday
+	to := last.Add(time.Nanosecond)
+	from := first
+	if *window > 0 {
+		from = to.Add(-*window)
+	}
+	summary := incidents.Summarize(from, to)
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(summary)
+		return
+	}
+	summary.Write(os.Stdout)
+}
+
+// printSummaries prints a summary of the last interval every interval
+func printSummaries(ctx context.Context, incidents *history.History, interval time.Duration, w io.Writer) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			// One write, so it doesn't interleave with explanations
+			var text bytes.Buffer
+			incidents.Summarize(now.Add(-interval), now).Write(&text)
+			w.Write(text.Bytes())
+		}
+	}
+}
+
+// loadIncidents replays the failures and recoveries of recordings in time
+// order, whatever order the files come in, so recoveries find their failure.
+// It returns the time span the recordings cover.
+func loadIncidents(paths []string) (incidents *history.History, first, last time.Time, err error) {
+	var events []notifier.Event
+	for _, path := range paths {
+		found, from, to, err := readEvents(path)
+		if err != nil {
+			return nil, first, last, fmt.Errorf("reading %s: %w", path, err)
+		}
+		events = append(events, found...)
+		if first.IsZero() || from.Before(first) {
+			first = from
+		}
+		if to.After(last) {
+			last = to
+		}
+	}
+
+	slices.SortStableFunc(events, func(a, b notifier.Event) int {
+		return a.Time.Compare(b.Time)
+	})
+	incidents = history.New(0)
+	for _, event := range events {
+		incidents.Add(event)
+	}
+	return incidents, first, last, nil
+}
+
+// readEvents reads the failures and recoveries of a recording and the time
+// span it covers
+func readEvents(path string) (events []notifier.Event, first, last time.Time, err error) {
+	reader, err := recording.Open(path)
+	if err != nil {
+		return nil, first, last, err
+	}
+	defer reader.Close()
+
+	for {
+		entry, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return events, first, last, nil
+		}
+		if err != nil {
+			return nil, first, last, err
+		}
+
+		if first.IsZero() {
+			first = entry.Time
+		}
+		last = entry.Time
+
+		if entry.Kind == recording.KindEvent && entry.Failure != nil {
+			events = append(events, notifier.Event{Type: entry.EventType, Time: entry.Time, Failure: *entry.Failure, Recovery: entry.Recovery})
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/explainer"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/notifier"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/recording"
+)
+
+// writeRecording writes entries as 'watch --record' does
+func writeRecording(t *testing.T, path string, entries ...recording.Entry) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	defer gz.Close()
+
+	encoder := json.NewEncoder(gz)
+	header := recording.Entry{Kind: recording.KindHeader, Header: &recording.Header{Version: recording.Version, Namespace: "shop"}}
+	for _, entry := range append([]recording.Entry{header}, entries...) {
+		if err := encoder.Encode(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadIncidentsNewestFileFirst(t *testing.T) {
+	failedAt := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	recoveredAt := failedAt.Add(10 * time.Minute)
+	info := explainer.FailureInfo{Namespace: "shop", PodName: "api-1", ContainerName: "app", Reason: "CrashLoopBackOff"}
+
+	dir := t.TempDir()
+	older, newer := filepath.Join(dir, "older.jsonl.gz"), filepath.Join(dir, "newer.jsonl.gz")
+	writeRecording(t, older, recording.Entry{Time: failedAt, Kind: recording.KindEvent, EventType: notifier.EventFailure, Failure: &info})
+	writeRecording(t, newer, recording.Entry{Time: recoveredAt, Kind: recording.KindEvent, EventType: notifier.EventRecovery, Failure: &info,
+		Recovery: &notifier.Recovery{StartedAt: failedAt, RecoveredAt: recoveredAt, DurationSeconds: 600}})
+
+	incidents, first, last, err := loadIncidents([]string{newer, older})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !first.Equal(failedAt) || !last.Equal(recoveredAt) {
+		t.Errorf("recordings span %s to %s, want %s to %s", first, last, failedAt, recoveredAt)
+	}
+
+	summary := incidents.Summarize(first, last.Add(time.Nanosecond))
+	if summary.Open != 0 || summary.Recovered != 1 || summary.MeanTimeToRecovery != 10*time.Minute {
+		t.Errorf("%d open, %d recovered, MTTR %s; want the incident recovered after 10m0s",
+			summary.Open, summary.Recovered, summary.MeanTimeToRecovery)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/detector"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/logging"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/tui"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// runTUI shows failing pods in an interactive terminal dashboard
+func runTUI(args []string) {
+	fs := newFlagSet("tui", "tui [-n NS | -A] [flags]")
+	var kube kubeFlags
+	kube.register(fs)
+	podName := fs.String("pod", "", "(optional) specific pod name to monitor (e.g., 'nginx-abc123')")
+	labelSelector := fs.String("labels", "", "(optional) label selector (e.g., 'app=nginx,tier=frontend')")
+	allNamespaces := fs.Bool("all-namespaces", false, "watch every namespace")
+	fs.BoolVar(allNamespaces, "A", false, "shorthand for --all-namespaces")
+	interval := fs.Duration("interval", detector.DefaultInterval, "how often pods are checked")
+	verbosity := fs.Int("v", 0, "log verbosity: 0 logs info and above, 1 adds debug messages, higher is more verbose")
+	logFile := fs.String("log-file", "", "(optional) file to write logs to; logs are discarded otherwise, as they would garble the screen")
+	fs.Parse(args)
+
+	logOutput := io.Discard
+	if *logFile != "" {
+		f, err := os.OpenFile(*logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening log file: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		logOutput = f
+	}
+	if err := logging.Setup(logOutput, *verbosity, "text"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	namespace := resolveNamespace(kube)
+	if *allNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := tui.Run(ctx, newClientset(kube), namespace, detector.Options{
+		PodName:       *podName,
+		LabelSelector: *labelSelector,
+		Interval:      *interval,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// version is set for releases with -ldflags "-X main.version=v1.2.3"
+var version = "dev"
+
+func runVersion(args []string) {
+	fmt.Printf("%s %s", programName(), version)
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				fmt.Printf(" (%s)", setting.Value[:min(len(setting.Value), 12)])
+			}
+		}
+	}
+	fmt.Printf(" %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+}
@@ -0,0 +1,411 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/detector"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/health"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/history"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/leader"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/metrics"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/notifier"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/poddiagnosis"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/preflight"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/recording"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/telemetry"
+	"github.com/Maniratnam557/k8s-pod-detective/pkg/webui"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// notifierDrainTimeout bounds how long shutdown waits for queued notifications,
+// well inside the default 30s pod termination grace period
+const notifierDrainTimeout = 20 * time.Second
+
+// runWatch watches pods until interrupted, or prints the RBAC rules it needs
+func runWatch(args []string, printRBAC bool) {
+	if err := watch(args, printRBAC); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// watch does the work of runWatch. It returns rather than exits, so the
+// deferred cleanup flushes queued notifications, the recording and telemetry.
+func watch(args []string, printRBAC bool) error {
+	fs := newFlagSet("watch", "watch [flags]")
+	if printRBAC {
+		fs = newFlagSet("rbac", "rbac [flags]")
+	}
+
+	var kube kubeFlags
+	kube.register(fs)
+	var logs logFlags
+	logs.register(fs)
+
+	podName := fs.String("pod", "", "(optional) specific pod name to monitor (e.g., 'nginx-abc123')")
+
+	labelSelector := fs.String("labels", "", "(optional) label selector (e.g., 'app=nginx,tier=frontend')")
+
+	webhookURL := fs.String("webhook-url", "", "(optional) URL to POST a JSON payload to for every failure and recovery")
+	webhookSecret := fs.String("webhook-secret", os.Getenv("DETECTIVE_WEBHOOK_SECRET"),
+		"(optional) HMAC-SHA256 secret used to sign webhook payloads (default $DETECTIVE_WEBHOOK_SECRET)")
+	var webhookHeaders stringSlice
+	fs.Var(&webhookHeaders, "webhook-header", "(optional) extra webhook header as 'Name: value' (repeatable)")
+	webhookRetries := fs.Int("webhook-retries", 5, "number of webhook delivery retries with exponential backoff")
+	webhookDeadLetter := fs.String("webhook-dead-letter", "", "(optional) JSONL file for webhook payloads that could not be delivered")
+
+	alertmanagerURL := fs.String("alertmanager-url", "", "(optional) Alertmanager base URL to push alerts to (e.g., 'http://alertmanager:9093')")
+	alertmanagerRefresh := fs.Duration("alertmanager-refresh", time.Minute, "how often active alerts are re-sent to Alertmanager")
+	alertmanagerRunbook := fs.String("alertmanager-runbook-url", "", "(optional) runbook_url annotation added to every alert")
+	var alertmanagerLabels stringSlice
+	fs.Var(&alertmanagerLabels, "alertmanager-label", "(optional) extra alert label as 'name=value' (repeatable)")
+
+	emitEvents := fs.Bool("emit-events", false, "record a Warning Event with the diagnosis on each failing pod")
+
+	writeDiagnoses := fs.Bool("write-diagnoses", false, "write a PodDiagnosis resource per incident (requires the CRD in config/crd)")
+
+	metricsAddr := fs.String("metrics-addr", "", "(optional) address to serve Prometheus metrics on (e.g., ':9090')")
+
+	otlpEndpoint := fs.String("otlp-endpoint", "", "(optional) OTLP collector endpoint for failure logs and detector traces (e.g., 'localhost:4317')")
+	otlpProtocol := fs.String("otlp-protocol", "grpc", "OTLP protocol: grpc or http")
+	otlpInsecure := fs.Bool("otlp-insecure", false, "disable TLS for the OTLP exporter")
+
+	interval := fs.Duration("interval", detector.DefaultInterval, "how often pods are checked")
+	healthAddr := fs.String("health-addr", "", "(optional) address to serve /healthz and /readyz on (e.g., ':8081')")
+	healthStaleIntervals := fs.Int("health-stale-intervals", 3, "/healthz fails after this many poll intervals without progress")
+	enablePprof := fs.Bool("pprof", false, "serve /debug/pprof/ on the health address")
+	uiAddr := fs.String("ui-addr", "", "(optional) address to serve the web UI of current and recent incidents on (e.g., ':8080')")
+
+	leaderElect := fs.Bool("leader-elect", false, "use a Lease so only one of several replicas reports failures")
+	leaderElectNamespace := fs.String("leader-elect-namespace", "", "namespace of the leader election Lease (default: the detector's own namespace)")
+	leaderElectLease := fs.String("leader-elect-lease", "k8s-pod-detective", "name of the leader election Lease")
+	leaderElectIdentity := fs.String("leader-elect-identity", "", "identity of this replica (default: hostname)")
+
+	skipPreflight := fs.Bool("skip-preflight", false, "don't check RBAC permissions before starting")
+	clusterRole := fs.Bool("cluster-role", false, "with 'rbac': print a ClusterRole instead of namespaced Roles")
+	serviceAccount := fs.String("service-account", "k8s-pod-detective",
+		"with 'rbac': the detector's ServiceAccount as 'name' or 'namespace/name', bound to every generated Role and ClusterRole")
+
+	clustersFile := fs.String("clusters-file", "", "(optional) YAML file listing clusters to watch concurrently (name, kubeconfig, context, namespace)")
+
+	summaryInterval := fs.Duration("summary-interval", 0, "(optional) print a summary of the incidents of the last interval this often (e.g., '24h')")
+
+	recordFile := fs.String("record", "", "(optional) gzipped file to record every pod list, log fetch and reported failure to, for 'replay'")
+
+	fs.Parse(args)
+	namespace := resolveNamespace(kube)
+
+	leaseNamespace := *leaderElectNamespace
+	if leaseNamespace == "" {
+		leaseNamespace = leader.DefaultNamespace()
+	}
+
+	if printRBAC {
+		requirements := preflight.Requirements(preflight.Features{
+			Namespace:      namespace,
+			EmitEvents:     *emitEvents,
+			WriteDiagnoses: *writeDiagnoses,
+			LeaderElection: *leaderElect,
+			LeaseNamespace: leaseNamespace,
+		})
+		subject := rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Namespace: leader.DefaultNamespace(), Name: *serviceAccount}
+		if ns, name, ok := strings.Cut(*serviceAccount, "/"); ok {
+			subject.Namespace, subject.Name = ns, name
+		}
+		manifest, err := preflight.RBACManifest("k8s-pod-detective", requirements, *clusterRole, subject)
+		if err != nil {
+			return fmt.Errorf("generating RBAC manifest: %w", err)
+		}
+		os.Stdout.Write(manifest)
+		return nil
+	}
+
+	logs.setup()
+
+	clusters, err := resolveClusters(kube, *clustersFile, namespace)
+	if err != nil {
+		return err
+	}
+	multiCluster := len(clusters) > 1
+
+	// Print banner
+	printBanner()
+
+	if *podName != "" && *labelSelector != "" {
+		return errors.New("cannot use both --pod and --labels together")
+	}
+
+	opts := detector.Options{
+		PodName:        *podName,
+		LabelSelector:  *labelSelector,
+		Interval:       *interval,
+		LeaderElection: *leaderElect,
+	}
+
+	// Clusters share stdout, so explanations must be written whole
+	if multiCluster {
+		opts.Output = &syncWriter{w: os.Stdout}
+	}
+
+	if *recordFile != "" {
+		if multiCluster {
+			return errors.New("--record supports a single cluster")
+		}
+
+		recorder, err := recording.Create(*recordFile, recording.Header{
+			Namespace:     clusters[0].Namespace,
+			PodName:       *podName,
+			LabelSelector: *labelSelector,
+			Interval:      *interval,
+		})
+		if err != nil {
+			return err
+		}
+		defer recorder.Close()
+		opts.Observer = recorder
+	}
+
+	// Set up notifiers
+	var sinks []notifier.Notifier
+
+	if *webhookURL != "" {
+		headers, err := parseHeaders(webhookHeaders)
+		if err != nil {
+			return err
+		}
+
+		webhook, err := notifier.NewWebhook(notifier.WebhookConfig{
+			URL:            *webhookURL,
+			Secret:         *webhookSecret,
+			Headers:        headers,
+			Timeout:        10 * time.Second,
+			MaxRetries:     *webhookRetries,
+			DeadLetterPath: *webhookDeadLetter,
+		})
+		if err != nil {
+			return fmt.Errorf("creating webhook notifier: %w", err)
+		}
+		sinks = append(sinks, webhook)
+	}
+
+	if *alertmanagerURL != "" {
+		labels, err := parseLabels(alertmanagerLabels)
+		if err != nil {
+			return err
+		}
+
+		alertmanager, err := notifier.NewAlertmanager(notifier.AlertmanagerConfig{
+			URL:             *alertmanagerURL,
+			RefreshInterval: *alertmanagerRefresh,
+			RunbookURL:      *alertmanagerRunbook,
+			Labels:          labels,
+		})
+		if err != nil {
+			return fmt.Errorf("creating Alertmanager notifier: %w", err)
+		}
+		sinks = append(sinks, alertmanager)
+	}
+
+	if *otlpEndpoint != "" {
+		provider, err := telemetry.Setup(context.Background(), telemetry.Config{
+			Endpoint: *otlpEndpoint,
+			Protocol: *otlpProtocol,
+			Insecure: *otlpInsecure,
+		})
+		if err != nil {
+			return fmt.Errorf("setting up OpenTelemetry: %w", err)
+		}
+		defer provider.Shutdown(context.Background())
+		sinks = append(sinks, provider.LogNotifier())
+	}
+
+	var dashboard *webui.Dashboard
+	if *uiAddr != "" {
+		dashboard = webui.New()
+		sinks = append(sinks, dashboard)
+	}
+
+	var incidents *history.History
+	if *summaryInterval > 0 {
+		incidents = history.New(*summaryInterval)
+		sinks = append(sinks, incidents)
+	}
+
+	// One dispatcher for all clusters, so each sink keeps a single queue
+	if len(sinks) > 0 {
+		dispatcher := notifier.NewDispatcher(sinks...)
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), notifierDrainTimeout)
+			defer cancel()
+			if err := dispatcher.Close(ctx); err != nil {
+				slog.Warn("Notifiers did not finish before shutdown", "error", err)
+			}
+		}()
+		opts.Notifier = dispatcher
+	}
+
+	if *metricsAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		go serveHTTP("metrics", *metricsAddr, mux)
+	}
+
+	// ===== ONE DETECTOR PER CLUSTER =====
+	var (
+		group     detectorGroup
+		watched   []clusterTarget
+		leaseHome *kubernetes.Clientset
+	)
+	for i, cluster := range clusters {
+		// Works both in-cluster and out-of-cluster
+		config, err := buildConfig(cluster.flags(kube))
+		if err != nil {
+			return fmt.Errorf("building config for cluster %q: %w", cluster.Name, err)
+		}
+
+		// Debug output
+		slog.Debug("Connecting to API server", "cluster", cluster.Name, "url", config.Host)
+
+		// Create clientset
+		clientset, err := kubernetes.NewForConfig(config)
+		if err != nil {
+			return fmt.Errorf("creating client for cluster %q: %w", cluster.Name, err)
+		}
+
+		// The leader election Lease lives in the first cluster
+		if i == 0 {
+			leaseHome = clientset
+		}
+
+		if !*skipPreflight {
+			requirements := preflight.Requirements(preflight.Features{
+				Namespace:      cluster.Namespace,
+				EmitEvents:     *emitEvents,
+				WriteDiagnoses: *writeDiagnoses,
+				LeaderElection: *leaderElect && i == 0,
+				LeaseNamespace: leaseNamespace,
+			})
+			results, err := preflight.Check(context.Background(), clientset, requirements)
+			if err != nil {
+				slog.Warn("Skipping preflight permission checks", "cluster", cluster.Name, "error", err)
+			} else {
+				if multiCluster {
+					fmt.Fprintf(os.Stderr, "Cluster: %s\n", cluster.Name)
+				}
+				if !preflight.Report(os.Stderr, results) {
+					if !multiCluster {
+						return errors.New("missing required permissions")
+					}
+					slog.Error("Not watching cluster, missing required permissions", "cluster", cluster.Name)
+					continue
+				}
+			}
+		}
+
+		clusterOpts := opts
+		clusterOpts.Cluster = cluster.Name
+
+		if *emitEvents {
+			recorder, stopRecorder := detector.NewEventRecorder(clientset)
+			defer stopRecorder()
+			clusterOpts.Recorder = recorder
+		}
+
+		if *writeDiagnoses {
+			dynamicClient, err := dynamic.NewForConfig(config)
+			if err != nil {
+				return fmt.Errorf("creating dynamic client: %w", err)
+			}
+			clusterOpts.Diagnoses = poddiagnosis.NewWriter(dynamicClient, time.Minute)
+		}
+
+		if dashboard != nil {
+			dashboard.AddCluster(cluster.Name, clientset)
+		}
+
+		group = append(group, detector.New(clientset, clusterOpts))
+		watched = append(watched, cluster)
+	}
+
+	if len(group) == 0 {
+		return errors.New("no cluster can be watched")
+	}
+
+	if *healthAddr != "" {
+		mux := health.NewMux(group, health.Config{
+			MaxStaleness: time.Duration(*healthStaleIntervals) * *interval,
+			Pprof:        *enablePprof,
+		})
+		go serveHTTP("health", *healthAddr, mux)
+	}
+
+	if dashboard != nil {
+		go serveHTTP("ui", *uiAddr, dashboard.Handler())
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if incidents != nil {
+		output := opts.Output
+		if output == nil {
+			output = os.Stdout
+		}
+		go printSummaries(ctx, incidents, *summaryInterval, output)
+	}
+
+	// Leader election failing stops the watch
+	leaderErr := make(chan error, 1)
+	if *leaderElect {
+		go func() {
+			err := leader.Run(ctx, leaseHome, leader.Config{
+				Namespace:     leaseNamespace,
+				LeaseName:     *leaderElectLease,
+				Identity:      *leaderElectIdentity,
+				LeaseDuration: 15 * time.Second,
+				RenewDeadline: 10 * time.Second,
+				RetryPeriod:   2 * time.Second,
+			}, group.SetLeading)
+			if err != nil {
+				leaderErr <- fmt.Errorf("running leader election: %w", err)
+				stop()
+			}
+		}()
+	}
+
+	if !multiCluster {
+		if err := group[0].WatchPods(ctx, watched[0].Namespace); err != nil {
+			return fmt.Errorf("watching pods: %w", err)
+		}
+	} else {
+		var wg sync.WaitGroup
+		for i, podDetector := range group {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				watchCluster(ctx, podDetector, watched[i])
+			}()
+		}
+		wg.Wait()
+	}
+
+	select {
+	case err := <-leaderErr:
+		return err
+	default:
+		return nil
+	}
+}